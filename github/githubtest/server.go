@@ -0,0 +1,325 @@
+// Package githubtest provides a fake GitHub release server for testing the version and upgrade commands offline
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// apiPath is the path prefix of the API, the same as the GitHub Enterprise
+const apiPath = "/api/v3"
+
+// Server is a fake GitHub server which keeps the releases and tags in memory
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	releases map[string][]*Release
+	tags     map[string][]string
+	nextID   int64
+
+	// the count of the remaining requests, it's unlimited if it's negative
+	remaining int
+	limit     int
+	reset     time.Time
+}
+
+// Release is a release of a repository
+type Release struct {
+	ID          int64
+	TagName     string
+	Name        string
+	Body        string
+	Draft       bool
+	Prerelease  bool
+	PublishedAt time.Time
+	Assets      []Asset
+}
+
+// Asset is a file which is attached to a release
+type Asset struct {
+	ID          int64
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// NewServer starts a fake GitHub server, don't forget to close it
+func NewServer() (server *Server) {
+	server = &Server{
+		releases:  map[string][]*Release{},
+		tags:      map[string][]string{},
+		remaining: -1,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return
+}
+
+// Client returns a GitHub client which talks to the fake server
+func (s *Server) Client() (client *github.Client) {
+	client = github.NewClient(nil)
+	baseURL, _ := url.Parse(s.URL + apiPath + "/")
+	client.BaseURL = baseURL
+	client.UploadURL = baseURL
+	return
+}
+
+// AddRelease adds a release to a repository, the tag of the release will be added as well
+func (s *Server) AddRelease(owner, repo string, release Release) *Server {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := repoKey(owner, repo)
+	if release.ID == 0 {
+		release.ID = s.newID()
+	}
+	for i := range release.Assets {
+		if release.Assets[i].ID == 0 {
+			release.Assets[i].ID = s.newID()
+		}
+	}
+	if release.PublishedAt.IsZero() {
+		release.PublishedAt = time.Now()
+	}
+	s.releases[key] = append(s.releases[key], &release)
+	s.tags[key] = append(s.tags[key], release.TagName)
+	return s
+}
+
+// AddAsset adds an asset into an exist release
+func (s *Server) AddAsset(owner, repo, tag string, asset Asset) (err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	release := s.findRelease(owner, repo, tag)
+	if release == nil {
+		err = fmt.Errorf("cannot find release %s of %s/%s", tag, owner, repo)
+		return
+	}
+	if asset.ID == 0 {
+		asset.ID = s.newID()
+	}
+	release.Assets = append(release.Assets, asset)
+	return
+}
+
+// AddTag adds a tag which does not belong to any release
+func (s *Server) AddTag(owner, repo, tag string) *Server {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := repoKey(owner, repo)
+	s.tags[key] = append(s.tags[key], tag)
+	return s
+}
+
+// SetRateLimit sets the count of the API requests before hitting the rate limit,
+// it's unlimited if the count is negative
+func (s *Server) SetRateLimit(count int, reset time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.limit = count
+	s.remaining = count
+	s.reset = reset
+}
+
+// DownloadURL returns the download URL of an asset, it's same with the browser download URL of GitHub
+func (s *Server) DownloadURL(owner, repo, tag, name string) string {
+	return fmt.Sprintf("%s/%s/%s/releases/download/%s/%s", s.URL, owner, repo, tag, name)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, apiPath+"/") {
+		s.serveDownload(w, r)
+		return
+	}
+
+	if s.remaining >= 0 {
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.reset.Unix(), 10))
+		if s.remaining == 0 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			writeError(w, http.StatusForbidden, "API rate limit exceeded")
+			return
+		}
+		s.remaining--
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.remaining))
+	}
+
+	// the path looks like: /repos/{owner}/{repo}/{resource}...
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPath), "/"), "/")
+	if len(parts) < 4 || parts[0] != "repos" || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	owner, repo, resource := parts[1], parts[2], parts[3:]
+	key := repoKey(owner, repo)
+
+	switch {
+	case len(resource) == 1 && resource[0] == "tags":
+		// the newest tag is the first one
+		tags := []*github.RepositoryTag{}
+		for i := len(s.tags[key]) - 1; i >= 0; i-- {
+			tags = append(tags, &github.RepositoryTag{Name: github.String(s.tags[key][i])})
+		}
+		start, end := pageRange(r, len(tags))
+		writeJSON(w, tags[start:end])
+	case len(resource) == 1 && resource[0] == "releases":
+		releases := []*github.RepositoryRelease{}
+		for _, release := range s.sortedReleases(key) {
+			releases = append(releases, s.toRepositoryRelease(owner, repo, release))
+		}
+		start, end := pageRange(r, len(releases))
+		writeJSON(w, releases[start:end])
+	case len(resource) == 2 && resource[0] == "releases" && resource[1] == "latest":
+		for _, release := range s.sortedReleases(key) {
+			if !release.Draft && !release.Prerelease {
+				writeJSON(w, s.toRepositoryRelease(owner, repo, release))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+	case len(resource) == 3 && resource[0] == "releases" && resource[1] == "tags":
+		if release := s.findRelease(owner, repo, resource[2]); release != nil {
+			writeJSON(w, s.toRepositoryRelease(owner, repo, release))
+			return
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+	case len(resource) == 3 && resource[0] == "releases" && resource[2] == "assets":
+		id, _ := strconv.ParseInt(resource[1], 10, 64)
+		for _, release := range s.releases[key] {
+			if release.ID == id {
+				writeJSON(w, s.toRepositoryRelease(owner, repo, release).Assets)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveDownload serves the path like: /{owner}/{repo}/releases/download/{tag}/{name}
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 6 && parts[2] == "releases" && parts[3] == "download" {
+		if release := s.findRelease(parts[0], parts[1], parts[4]); release != nil {
+			for _, asset := range release.Assets {
+				if asset.Name == parts[5] {
+					w.Header().Set("Content-Type", asset.getContentType())
+					w.Header().Set("Content-Length", strconv.Itoa(len(asset.Content)))
+					_, _ = w.Write(asset.Content)
+					return
+				}
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) findRelease(owner, repo, tag string) *Release {
+	for _, release := range s.releases[repoKey(owner, repo)] {
+		if release.TagName == tag {
+			return release
+		}
+	}
+	return nil
+}
+
+// sortedReleases returns the releases order by the publish time, the newest one is the first
+func (s *Server) sortedReleases(key string) (releases []*Release) {
+	for i := len(s.releases[key]) - 1; i >= 0; i-- {
+		releases = append(releases, s.releases[key][i])
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].PublishedAt.After(releases[j].PublishedAt)
+	})
+	return
+}
+
+func (s *Server) toRepositoryRelease(owner, repo string, release *Release) *github.RepositoryRelease {
+	result := &github.RepositoryRelease{
+		ID:          github.Int64(release.ID),
+		TagName:     github.String(release.TagName),
+		Name:        github.String(release.Name),
+		Body:        github.String(release.Body),
+		Draft:       github.Bool(release.Draft),
+		Prerelease:  github.Bool(release.Prerelease),
+		PublishedAt: &github.Timestamp{Time: release.PublishedAt},
+	}
+	for _, asset := range release.Assets {
+		result.Assets = append(result.Assets, github.ReleaseAsset{
+			ID:                 github.Int64(asset.ID),
+			Name:               github.String(asset.Name),
+			ContentType:        github.String(asset.getContentType()),
+			Size:               github.Int(len(asset.Content)),
+			BrowserDownloadURL: github.String(s.DownloadURL(owner, repo, release.TagName, asset.Name)),
+		})
+	}
+	return result
+}
+
+func (a Asset) getContentType() string {
+	if a.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return a.ContentType
+}
+
+func repoKey(owner, repo string) string {
+	return owner + "/" + repo
+}
+
+// pageRange returns the range of a page according to the query parameters page and per_page
+func pageRange(r *http.Request, count int) (start, end int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 30
+	}
+
+	start, end = (page-1)*perPage, page*perPage
+	if start > count {
+		start = count
+	}
+	if end > count {
+		end = count
+	}
+	return
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"message":           message,
+		"documentation_url": "https://developer.github.com/v3",
+	})
+}
@@ -0,0 +1,92 @@
+package githubtest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/linuxsuren/cobra-extension/github/githubtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer() *githubtest.Server {
+	server := githubtest.NewServer()
+	server.AddRelease("o", "r", githubtest.Release{
+		TagName:     "v0.0.1",
+		PublishedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		Assets: []githubtest.Asset{{
+			Name:    "fake.tar.gz",
+			Content: []byte("fake"),
+		}},
+	}).AddRelease("o", "r", githubtest.Release{
+		TagName:     "v0.0.2",
+		Prerelease:  true,
+		PublishedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+	}).AddTag("o", "r", "v0.0.3")
+	return server
+}
+
+func TestReleases(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	latest, _, err := client.Repositories.GetLatestRelease(ctx, "o", "r")
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.0.1", latest.GetTagName())
+	assert.Equal(t, 1, len(latest.Assets))
+	assert.Equal(t, server.DownloadURL("o", "r", "v0.0.1", "fake.tar.gz"), latest.Assets[0].GetBrowserDownloadURL())
+
+	releases, _, err := client.Repositories.ListReleases(ctx, "o", "r", &github.ListOptions{PerPage: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(releases))
+	assert.Equal(t, "v0.0.2", releases[0].GetTagName())
+
+	release, _, err := client.Repositories.GetReleaseByTag(ctx, "o", "r", "v0.0.2")
+	assert.Nil(t, err)
+	assert.True(t, release.GetPrerelease())
+
+	_, _, err = client.Repositories.GetReleaseByTag(ctx, "o", "r", "v0.0.3")
+	assert.NotNil(t, err)
+
+	tags, _, err := client.Repositories.ListTags(ctx, "o", "r", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(tags))
+	assert.Equal(t, "v0.0.3", tags[0].GetName())
+}
+
+func TestAssets(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+
+	assert.NotNil(t, server.AddAsset("o", "r", "v0.0.9", githubtest.Asset{Name: "fake"}))
+	assert.Nil(t, server.AddAsset("o", "r", "v0.0.2", githubtest.Asset{Name: "checksums.txt", Content: []byte("abc")}))
+
+	resp, err := http.Get(server.DownloadURL("o", "r", "v0.0.2", "checksums.txt"))
+	assert.Nil(t, err)
+	data, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "abc", string(data))
+
+	resp, err = http.Get(server.DownloadURL("o", "r", "v0.0.2", "missing"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRateLimit(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+	server.SetRateLimit(1, time.Now().Add(time.Hour))
+	client := server.Client()
+
+	_, _, err := client.Repositories.GetLatestRelease(context.Background(), "o", "r")
+	assert.Nil(t, err)
+
+	_, _, err = client.Repositories.GetLatestRelease(context.Background(), "o", "r")
+	_, ok := err.(*github.RateLimitError)
+	assert.True(t, ok)
+}
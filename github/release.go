@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"golang.org/x/oauth2"
 
@@ -25,8 +26,13 @@ type ReleaseAsset struct {
 
 // Release represents a GitHub release
 type Release struct {
+	// TagName is the git tag of the release, it was the release name before
 	TagName string
 	ID      int64
+	// Name is the title of the release, it might be different from the tag
+	Name        string
+	Prerelease  bool
+	PublishedAt time.Time
 }
 
 // Tag represents a tag of a git repository
@@ -62,7 +68,8 @@ func (g *ReleaseClient) GetLatestReleaseAsset(owner, repo string) (ra *ReleaseAs
 	return
 }
 
-// GetReleaseList returns a list of release
+// GetReleaseList returns a list of release.
+// Please notice that the TagName comes from the git tag instead of the release name, see also Release.Name
 func (g *ReleaseClient) GetReleaseList(owner, repo string, count int) (list []Release, err error) {
 	ctx := context.Background()
 
@@ -71,8 +78,11 @@ func (g *ReleaseClient) GetReleaseList(owner, repo string, count int) (list []Re
 		for i := range releaseList {
 			release := releaseList[i]
 			list = append(list, Release{
-				TagName: *release.Name,
-				ID:      *release.ID,
+				TagName:     release.GetTagName(),
+				ID:          release.GetID(),
+				Name:        release.GetName(),
+				Prerelease:  release.GetPrerelease(),
+				PublishedAt: release.GetPublishedAt().Time,
 			})
 		}
 	}
@@ -2,8 +2,10 @@ package github_test
 
 import (
 	jClient "github.com/linuxsuren/cobra-extension/github"
+	"github.com/linuxsuren/cobra-extension/github/githubtest"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestInit(t *testing.T) {
@@ -43,3 +45,30 @@ func TestGetReleaseAssetByTagName(t *testing.T) {
 	assert.Equal(t, "tagName", asset.TagName)
 	assert.Equal(t, "body", asset.Body)
 }
+
+func TestGetReleaseList(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRelease("o", "r", githubtest.Release{
+		TagName:     "v0.0.1",
+		PublishedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+	}).AddRelease("o", "r", githubtest.Release{
+		TagName:     "v0.0.2",
+		Name:        "the second release",
+		Prerelease:  true,
+		PublishedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	ghClient := jClient.ReleaseClient{
+		Client: server.Client(),
+	}
+	list, err := ghClient.GetReleaseList("o", "r", 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, "v0.0.2", list[0].TagName)
+	assert.Equal(t, "the second release", list[0].Name)
+	assert.True(t, list[0].Prerelease)
+	assert.Equal(t, 2021, list[0].PublishedAt.Year())
+	assert.False(t, list[1].Prerelease)
+}
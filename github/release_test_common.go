@@ -9,6 +9,7 @@ import (
 )
 
 // PrepareForGetJCLIAsset only for test
+// Deprecated see also githubtest.Server
 func PrepareForGetJCLIAsset(ver string) (client *github.Client, teardown func()) {
 	var mux *http.ServeMux
 
@@ -22,6 +23,7 @@ func PrepareForGetJCLIAsset(ver string) (client *github.Client, teardown func())
 }
 
 // PrepareForGetReleaseAssetByTagName only for test
+// Deprecated see also githubtest.Server
 func PrepareForGetReleaseAssetByTagName() (client *github.Client, teardown func()) {
 	var mux *http.ServeMux
 
@@ -35,6 +37,7 @@ func PrepareForGetReleaseAssetByTagName() (client *github.Client, teardown func(
 }
 
 // PrepareForGetLatestJCLIAsset only for test
+// Deprecated see also githubtest.Server
 func PrepareForGetLatestJCLIAsset() (client *github.Client, teardown func()) {
 	var mux *http.ServeMux
 
@@ -48,6 +51,7 @@ func PrepareForGetLatestJCLIAsset() (client *github.Client, teardown func()) {
 }
 
 // PrepareForGetLatestReleaseAsset only for test
+// Deprecated see also githubtest.Server
 func PrepareForGetLatestReleaseAsset() (client *github.Client, teardown func()) {
 	var mux *http.ServeMux
 
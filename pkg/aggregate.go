@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AggregateSum is the sum of a numeric column
+	AggregateSum = "sum"
+	// AggregateAvg is the average of a numeric column
+	AggregateAvg = "avg"
+	// AggregateMin is the min value of a numeric column
+	AggregateMin = "min"
+	// AggregateMax is the max value of a numeric column
+	AggregateMax = "max"
+	// AggregateCount is the count of the rows
+	AggregateCount = "count"
+)
+
+// AggregateFuncs are all the supported aggregate functions
+var AggregateFuncs = []string{AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount}
+
+// totalLabel is the label of the footer if the first column has no aggregate
+const totalLabel = "TOTAL"
+
+// parseAggregates parses the aggregates like Size:sum, the key is the field
+func (o *OutputOption) parseAggregates() (aggregates map[string]string, err error) {
+	aggregates = map[string]string{}
+	for _, item := range o.Aggregates {
+		items := strings.SplitN(item, ":", 2)
+		field, fn := strings.TrimSpace(items[0]), AggregateSum
+		if len(items) > 1 {
+			fn = strings.TrimSpace(items[1])
+		}
+
+		if field == "" || !containsString(AggregateFuncs, fn) {
+			err = fmt.Errorf("invalid aggregate '%s', supported functions: %s", item, strings.Join(AggregateFuncs, ", "))
+			return
+		}
+		aggregates[field] = fn
+	}
+	return
+}
+
+// checkAggregates makes sure the aggregate fields are the columns, and the numeric functions work on the numbers
+func (o *OutputOption) checkAggregates(elemType reflect.Type) (err error) {
+	var aggregates map[string]string
+	if aggregates, err = o.parseAggregates(); err != nil {
+		return
+	}
+
+	if o.GroupBy != "" {
+		if err = checkFieldPath(elemType, o.GroupBy); err != nil {
+			err = fmt.Errorf("invalid group-by field %s, error: %v", o.GroupBy, err)
+			return
+		}
+	}
+
+	for field, fn := range aggregates {
+		var found bool
+		for _, col := range o.getColumns() {
+			found = found || col.field == field
+		}
+		if !found {
+			err = fmt.Errorf("invalid aggregate %s, it's not a column", field)
+			return
+		}
+
+		var fieldType reflect.Type
+		if fieldType, err = resolveFieldType(elemType, field); err != nil {
+			err = fmt.Errorf("invalid aggregate %s, error: %v", field, err)
+			return
+		} else if fieldType == nil || fn == AggregateCount {
+			continue
+		}
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if !isNumberKind(fieldType.Kind()) {
+			err = fmt.Errorf("invalid aggregate %s, %s works on the numbers only", field, fn)
+			return
+		}
+	}
+	return
+}
+
+// groupList moves the items of the same group together, the groups keep the order of the first items.
+// Only the table has the groups, the other formats keep the order of the items
+func (o *OutputOption) groupList(obj interface{}, format string) (result interface{}, err error) {
+	result = obj
+	if o.GroupBy == "" || (format != TableOutputFormat && format != "") {
+		return
+	}
+
+	items := reflect.ValueOf(obj)
+	var groups []string
+	members := map[string][]reflect.Value{}
+	for i := 0; i < items.Len(); i++ {
+		var group string
+		if group, err = ReflectFieldValueAsStringWithError(items.Index(i), o.GroupBy); err != nil {
+			return
+		}
+		if _, ok := members[group]; !ok {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], items.Index(i))
+	}
+
+	list := reflect.MakeSlice(reflect.SliceOf(items.Type().Elem()), 0, items.Len())
+	for _, group := range groups {
+		list = reflect.Append(list, members[group]...)
+	}
+	result = list.Interface()
+	return
+}
+
+// getFooter returns the aggregates of the items as a table row, it's nil if there's no aggregate
+func (o *OutputOption) getFooter(items reflect.Value) (footer []string, err error) {
+	var aggregates map[string]string
+	if aggregates, err = o.parseAggregates(); err != nil || len(aggregates) == 0 {
+		return
+	}
+
+	for i, col := range o.getColumns() {
+		fn, ok := aggregates[col.field]
+		if !ok {
+			label := ""
+			if i == 0 {
+				label = totalLabel
+			}
+			footer = append(footer, label)
+			continue
+		}
+
+		var cell string
+		if cell, err = aggregate(items, col.field, fn); err != nil {
+			return
+		}
+		if fn != AggregateCount {
+			if cell, err = o.renderCell(col, cell); err != nil {
+				return
+			}
+		}
+		footer = append(footer, cell)
+	}
+	return
+}
+
+// aggregate computes the value of the field of all the items
+func aggregate(items reflect.Value, field, fn string) (result string, err error) {
+	if fn == AggregateCount {
+		result = strconv.Itoa(items.Len())
+		return
+	}
+
+	var (
+		value     float64
+		valueType reflect.Type
+		// count is the count of the valid values, the nil values are skipped
+		count int
+	)
+	for i := 0; i < items.Len(); i++ {
+		var item reflect.Value
+		if item, err = ReflectFieldValue(items.Index(i), field); err != nil {
+			return
+		}
+		if item = indirectValue(item); !item.IsValid() {
+			continue
+		}
+
+		number := numberValue(item)
+		switch {
+		case valueType == nil:
+			value = number
+		case fn == AggregateMin:
+			value = math.Min(value, number)
+		case fn == AggregateMax:
+			value = math.Max(value, number)
+		default:
+			value += number
+		}
+		valueType = item.Type()
+		count++
+	}
+
+	if valueType == nil {
+		return
+	}
+	if fn == AggregateAvg {
+		value /= float64(count)
+	}
+	result = formatNumber(value, valueType)
+	return
+}
+
+// formatNumber formats the number as the type, e.g. a duration
+func formatNumber(value float64, valueType reflect.Type) string {
+	switch {
+	case valueType == durationType:
+		return time.Duration(value).String()
+	case isIntegerKind(valueType.Kind()) && value == math.Trunc(value):
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return isIntegerKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
@@ -0,0 +1,329 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BorderLine is a horizontal line of a table, it's not drawn if the fill is empty
+type BorderLine struct {
+	Left  string
+	Fill  string
+	Right string
+	// Cross joins the vertical lines above and below
+	Cross string
+	// CrossUp joins the vertical line above only
+	CrossUp string
+	// CrossDown joins the vertical line below only
+	CrossDown string
+}
+
+// BorderStyle is the box-drawing characters of a table
+type BorderStyle struct {
+	Top             BorderLine
+	HeaderSeparator BorderLine
+	RowSeparator    BorderLine
+	Bottom          BorderLine
+
+	// Left, Column and Right are the vertical lines, Column is the table separator if it's empty
+	Left    string
+	Column  string
+	Right   string
+	Padding string
+}
+
+func newBorderLine(left, fill, right, cross, crossUp, crossDown string) BorderLine {
+	return BorderLine{Left: left, Fill: fill, Right: right, Cross: cross, CrossUp: crossUp, CrossDown: crossDown}
+}
+
+func newBoxBorder(vertical, horizontal string, top, middle, bottom [3]string) *BorderStyle {
+	separator := newBorderLine(middle[0], horizontal, middle[2], middle[1], bottom[1], top[1])
+	return &BorderStyle{
+		Top:             newBorderLine(top[0], horizontal, top[2], top[1], horizontal, top[1]),
+		HeaderSeparator: separator,
+		RowSeparator:    separator,
+		Bottom:          newBorderLine(bottom[0], horizontal, bottom[2], bottom[1], bottom[1], horizontal),
+		Left:            vertical,
+		Column:          vertical,
+		Right:           vertical,
+		Padding:         " ",
+	}
+}
+
+// BorderNone is the borderless style
+var BorderNone = &BorderStyle{}
+
+// BorderStyles are the supported border styles, the key is the name
+var BorderStyles = map[string]*BorderStyle{
+	"none": BorderNone,
+	"ascii": newBoxBorder("|", "-",
+		[3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}),
+	"light": newBoxBorder("│", "─",
+		[3]string{"┌", "┬", "┐"}, [3]string{"├", "┼", "┤"}, [3]string{"└", "┴", "┘"}),
+	"heavy": newBoxBorder("┃", "━",
+		[3]string{"┏", "┳", "┓"}, [3]string{"┣", "╋", "┫"}, [3]string{"┗", "┻", "┛"}),
+	"rounded": newBoxBorder("│", "─",
+		[3]string{"╭", "┬", "╮"}, [3]string{"├", "┼", "┤"}, [3]string{"╰", "┴", "╯"}),
+	"markdown": {
+		HeaderSeparator: newBorderLine("|", "-", "|", "|", "|", "|"),
+		Left:            "|",
+		Column:          "|",
+		Right:           "|",
+		Padding:         " ",
+	},
+}
+
+// GetBorderStyle returns the border style by the name, an empty name means the borderless style
+func GetBorderStyle(name string) (style *BorderStyle, err error) {
+	if name == "" {
+		style = BorderNone
+		return
+	}
+
+	var ok bool
+	if style, ok = BorderStyles[name]; !ok {
+		err = fmt.Errorf("not support table style %s, supported styles: %s", name, strings.Join(GetBorderStyleNames(), ", "))
+	}
+	return
+}
+
+// GetBorderStyleNames returns the sorted names of the border styles
+func GetBorderStyleNames() (names []string) {
+	for name := range BorderStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// cellSpan is the count of the columns and rows which a cell takes
+type cellSpan struct {
+	columns int
+	rows    int
+}
+
+// segment is the part of a line which a cell takes
+type segment struct {
+	owner   cellPosition
+	column  int
+	columns int
+	// drawn is false if the cell continues from the row above
+	drawn bool
+}
+
+// SetCellSpan makes the cell take multiple columns and rows, the covered cells are ignored
+func (t *Table) SetCellSpan(row, column, columns, rows int) {
+	if t.cellSpans == nil {
+		t.cellSpans = map[cellPosition]cellSpan{}
+	}
+	t.cellSpans[cellPosition{row: row, column: column}] = cellSpan{columns: columns, rows: rows}
+}
+
+// getBorder returns the border style, the table separator is the column line of the borderless style
+func (t *Table) getBorder() BorderStyle {
+	border := BorderNone
+	if t.Border != nil {
+		border = t.Border
+	}
+
+	result := *border
+	if result.Column == "" {
+		result.Column = t.Separator
+	}
+	return result
+}
+
+// borderWidth returns the width of the vertical lines and paddings
+func (border BorderStyle) borderWidth(columns int) int {
+	if columns == 0 {
+		return 0
+	}
+	return Lenf(border.Left) + Lenf(border.Right) + (columns-1)*Lenf(border.Column) + 2*columns*Lenf(border.Padding)
+}
+
+// cellOwners returns the position of the cell which covers each column of each row
+func (t *Table) cellOwners(columns int) (owners [][]cellPosition) {
+	owners = make([][]cellPosition, len(t.Rows))
+	for ri := range owners {
+		owners[ri] = make([]cellPosition, columns)
+		for ci := range owners[ri] {
+			owners[ri][ci] = cellPosition{row: -1}
+		}
+	}
+
+	for ri := range owners {
+		for ci := range owners[ri] {
+			if owners[ri][ci].row >= 0 {
+				continue
+			}
+
+			position := cellPosition{row: ri, column: ci}
+			span := t.getCellSpan(position)
+			if t.groups[ri] {
+				span.columns = columns
+			}
+			for r := ri; r < ri+span.rows && r < len(owners); r++ {
+				for c := ci; c < ci+span.columns && c < columns; c++ {
+					if owners[r][c].row < 0 {
+						owners[r][c] = position
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (t *Table) getCellSpan(position cellPosition) (span cellSpan) {
+	span = t.cellSpans[position]
+	if span.columns < 1 {
+		span.columns = 1
+	}
+	if span.rows < 1 {
+		span.rows = 1
+	}
+	return
+}
+
+// getCell returns the text of the cell, it's empty if the row is shorter
+func (t *Table) getCell(position cellPosition) string {
+	if row := t.Rows[position.row]; position.column < len(row) {
+		return row[position.column]
+	}
+	return ""
+}
+
+// rowSegments returns the cells of the row from left to right
+func rowSegments(owners [][]cellPosition, row, columns int) (segments []segment) {
+	for ci := 0; ci < columns; {
+		owner := owners[row][ci]
+		item := segment{owner: owner, column: ci, columns: 1, drawn: owner.row == row}
+		for ci+item.columns < columns && owners[row][ci+item.columns] == owner {
+			item.columns++
+		}
+		segments = append(segments, item)
+		ci += item.columns
+	}
+	return
+}
+
+// segmentWidth returns the width of the cell which takes multiple columns
+func (border BorderStyle) segmentWidth(widths []int, item segment) (width int) {
+	for _, w := range widths[item.column : item.column+item.columns] {
+		width += w
+	}
+	return width + (item.columns-1)*(Lenf(border.Column)+2*Lenf(border.Padding))
+}
+
+// renderLine renders the horizontal line between the rows above and below, -1 or the count of rows means the edge
+func (t *Table) renderLine(border BorderStyle, line BorderLine, owners [][]cellPosition, above, below int, widths []int) {
+	if line.Fill == "" || len(widths) == 0 {
+		return
+	}
+
+	columns := len(widths)
+	isBoundary := func(row, column int) bool {
+		return row >= 0 && row < len(owners) &&
+			(column == 0 || column == columns || owners[row][column-1] != owners[row][column])
+	}
+	drawn := make([]bool, columns)
+	for ci := range drawn {
+		drawn[ci] = above < 0 || below >= len(owners) || owners[above][ci] != owners[below][ci]
+	}
+
+	var builder strings.Builder
+	for ci := 0; ci <= columns; ci++ {
+		left := ci > 0 && drawn[ci-1]
+		right := ci < columns && drawn[ci]
+		up, down := isBoundary(above, ci), isBoundary(below, ci)
+
+		switch {
+		case ci == 0 && !right:
+			builder.WriteString(border.Left)
+		case ci == 0:
+			builder.WriteString(line.Left)
+		case ci == columns && !left:
+			builder.WriteString(border.Right)
+		case ci == columns:
+			builder.WriteString(line.Right)
+		case !left && !right && (up || down):
+			builder.WriteString(border.Column)
+		case !left && !right:
+			builder.WriteString(strings.Repeat(" ", Lenf(border.Column)))
+		case !left:
+			builder.WriteString(line.Left)
+		case !right:
+			builder.WriteString(line.Right)
+		case up && down:
+			builder.WriteString(line.Cross)
+		case up:
+			builder.WriteString(line.CrossUp)
+		case down:
+			builder.WriteString(line.CrossDown)
+		default:
+			builder.WriteString(line.Fill)
+		}
+
+		if ci < columns {
+			width := widths[ci] + 2*Lenf(border.Padding)
+			if drawn[ci] {
+				builder.WriteString(strings.Repeat(line.Fill, width))
+			} else {
+				builder.WriteString(strings.Repeat(" ", width))
+			}
+		}
+	}
+	fmt.Fprintln(t.Out, builder.String())
+}
+
+// renderRow renders a row which might take multiple lines
+func (t *Table) renderRow(border BorderStyle, owners [][]cellPosition, row int, widths []int, colored bool) {
+	segments := rowSegments(owners, row, len(widths))
+	if border.Right == "" {
+		// keep the short rows of the borderless table
+		for len(segments) > 1 && segments[len(segments)-1].owner.column >= len(t.Rows[row]) {
+			segments = segments[:len(segments)-1]
+		}
+	}
+
+	cells := make([][]string, len(segments))
+	height := 1
+	for i, item := range segments {
+		var text string
+		if item.drawn {
+			text = t.getCell(item.owner)
+		}
+		cells[i] = fitCell(text, border.segmentWidth(widths, item), t.GetColumnOverflow(item.owner.column))
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+
+	for li := 0; li < height; li++ {
+		var builder strings.Builder
+		builder.WriteString(border.Left)
+		for i, item := range segments {
+			if i > 0 {
+				builder.WriteString(border.Column)
+			}
+
+			var text string
+			if li < len(cells[i]) {
+				if text = cells[i][li]; colored {
+					text = t.GetCellStyle(item.owner.row, item.owner.column).render(text)
+				} else {
+					text = StripANSI(text)
+				}
+			}
+			align := t.GetColumnAlign(item.owner.column)
+			if i == len(segments)-1 && border.Right == "" && align != AlignCenter && align != AlignRight {
+				builder.WriteString(border.Padding + text)
+			} else {
+				builder.WriteString(border.Padding + Pad(text, " ", border.segmentWidth(widths, item), align) + border.Padding)
+			}
+		}
+		builder.WriteString(border.Right)
+		fmt.Fprintln(t.Out, builder.String())
+	}
+}
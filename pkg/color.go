@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const ansiReset = "\x1b[0m"
+
+// ansiPattern matches the ANSI escape sequences, e.g. \x1b[31m
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*[a-zA-Z]")
+
+const (
+	// ColorAuto enables the color when the output is a terminal and NO_COLOR is not set
+	ColorAuto = "auto"
+	// ColorAlways enables the color
+	ColorAlways = "always"
+	// ColorNever disables the color
+	ColorNever = "never"
+)
+
+// ColorModes are all the supported color modes
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// colorMode is the default color mode, see also SetColorMode
+var colorMode = ColorAuto
+
+// SetColorMode sets the default color mode, it should be one of auto, always and never.
+// The color mode of an OutputOption or a Table takes precedence over it
+func SetColorMode(mode string) (err error) {
+	if err = checkColorMode(mode); err == nil && mode != "" {
+		colorMode = mode
+	}
+	return
+}
+
+// checkColorMode returns an error if the color mode is not supported, the empty mode is the default one
+func checkColorMode(mode string) (err error) {
+	if mode != "" && !containsString(ColorModes, mode) {
+		err = fmt.Errorf("not support color mode %s, supported modes: %s", mode, strings.Join(ColorModes, ", "))
+	}
+	return
+}
+
+// getColorMode returns the color mode, or the default one if it's empty
+func getColorMode(mode string) string {
+	if mode == "" {
+		return colorMode
+	}
+	return mode
+}
+
+// Color is the ANSI color of the text
+type Color int
+
+const (
+	// ColorDefault keeps the color of the terminal
+	ColorDefault Color = iota
+	// ColorBlack is black
+	ColorBlack
+	// ColorRed is red
+	ColorRed
+	// ColorGreen is green
+	ColorGreen
+	// ColorYellow is yellow
+	ColorYellow
+	// ColorBlue is blue
+	ColorBlue
+	// ColorMagenta is magenta
+	ColorMagenta
+	// ColorCyan is cyan
+	ColorCyan
+	// ColorWhite is white
+	ColorWhite
+)
+
+// Style is the style of the text
+type Style struct {
+	Foreground Color
+	Background Color
+	Bold       bool
+	Dim        bool
+}
+
+// Render wraps the text with the ANSI escape sequences of the style unless the default color mode disables it.
+// In the auto mode, the writer decides if the color shows, the tables and outputs strip the escape
+// sequences when the writer is not a terminal
+func (s Style) Render(text string) string {
+	if colorDisabled("") {
+		return text
+	}
+	return s.render(text)
+}
+
+// render wraps the text with the ANSI escape sequences of the style whatever the color mode is
+func (s Style) render(text string) string {
+	codes := s.codes()
+	if text == "" || len(codes) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + ansiReset
+}
+
+func (s Style) codes() (codes []string) {
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Dim {
+		codes = append(codes, "2")
+	}
+	if s.Foreground != ColorDefault {
+		codes = append(codes, strconv.Itoa(29+int(s.Foreground)))
+	}
+	if s.Background != ColorDefault {
+		codes = append(codes, strconv.Itoa(39+int(s.Background)))
+	}
+	return
+}
+
+// colorize wraps the text with the foreground color if the color is enabled
+func colorize(text string, color Color) string {
+	return Style{Foreground: color}.Render(text)
+}
+
+// colorDisabled returns true if the color mode is never, or the environment NO_COLOR exists in the
+// auto mode, see also https://no-color.org
+func colorDisabled(mode string) bool {
+	switch getColorMode(mode) {
+	case ColorAlways:
+		return false
+	case ColorNever:
+		return true
+	}
+	_, ok := os.LookupEnv("NO_COLOR")
+	return ok
+}
+
+// colorEnabled returns true if the color shows in the writer. In the auto mode, the writer must be a terminal
+func colorEnabled(mode string, out io.Writer) bool {
+	if colorDisabled(mode) {
+		return false
+	} else if getColorMode(mode) == ColorAlways {
+		return true
+	}
+
+	file, ok := terminalFile(out)
+	return ok && isTerminal(file)
+}
+
+// colorFor removes the ANSI escape sequences from the text if the color does not show in the writer
+func colorFor(mode string, out io.Writer, text string) string {
+	if colorEnabled(mode, out) {
+		return text
+	}
+	return StripANSI(text)
+}
+
+// isTerminal returns true if the file is a character device, e.g. a terminal
+func isTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// StripANSI removes the ANSI escape sequences from the text
+func StripANSI(text string) string {
+	if !strings.Contains(text, "\x1b") {
+		return text
+	}
+	return ansiPattern.ReplaceAllString(text, "")
+}
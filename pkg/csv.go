@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+)
+
+// tsvEscaper escapes the characters which are not allowed in a TSV field
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+func (o *OutputOption) outputSeparatedValues(obj interface{}, format string) (err error) {
+	writer := csv.NewWriter(o.Writer)
+	if format == TSVOutputFormat {
+		writer.Comma = '\t'
+	}
+
+	write := func(record []string) error {
+		// the escape sequences of the colors are never a part of the data
+		line := make([]string, 0, len(record))
+		for _, field := range record {
+			field = StripANSI(field)
+			if format == TSVOutputFormat {
+				// there's no quote in TSV, the special characters need to be escaped
+				field = tsvEscaper.Replace(field)
+			}
+			line = append(line, field)
+		}
+
+		if format == TSVOutputFormat {
+			_, err := o.Writer.Write([]byte(strings.Join(line, "\t") + "\n"))
+			return err
+		}
+		return writer.Write(line)
+	}
+
+	if !o.WithoutHeaders {
+		if err = write(o.GetHeaders()); err != nil {
+			return
+		}
+	}
+
+	items := reflect.ValueOf(obj)
+	for i := 0; i < items.Len(); i++ {
+		var line []string
+		if line, err = o.getLine(items.Index(i)); err != nil {
+			return
+		}
+		if err = write(line); err != nil {
+			return
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	return
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Separated values and markdown output test", func() {
+	type fakeItem struct {
+		Name        string
+		Description string
+	}
+
+	var (
+		buffer *bytes.Buffer
+		opt    *OutputOption
+		items  []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns: "Name,Description",
+			Writer:  buffer,
+			CellRenderMap: map[string]RenderCell{
+				"Name": strings.ToUpper,
+			},
+		}
+		items = []fakeItem{{
+			Name:        "a",
+			Description: `say "hi", bye`,
+		}, {
+			Name:        "b",
+			Description: "a|b\tc\nd",
+		}}
+	})
+
+	It("csv", func() {
+		opt.Format = CSVOutputFormat
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`Name,Description
+A,"say ""hi"", bye"
+B,"a|b	c
+d"
+`))
+	})
+
+	It("tsv without headers", func() {
+		opt.Format = TSVOutputFormat
+		opt.WithoutHeaders = true
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("A\tsay \"hi\", bye\nB\ta|b\\tc\\nd\n"))
+	})
+
+	DescribeTable("markdown",
+		func(withoutHeaders bool) {
+			opt.Format = MarkdownOutputFormat
+			opt.WithoutHeaders = withoutHeaders
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(Equal(`| Name | Description |
+|---|---|
+| A | say "hi", bye |
+| B | a\|b	c<br>d |
+`))
+		},
+		Entry("with headers", false),
+		Entry("the headers are always written", true),
+	)
+})
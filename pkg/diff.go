@@ -0,0 +1,348 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DiffAdded means the item only exists in the new list
+	DiffAdded = "added"
+	// DiffRemoved means the item only exists in the old list
+	DiffRemoved = "removed"
+	// DiffChanged means some fields of the item are changed
+	DiffChanged = "changed"
+)
+
+// diffMarkers are the markers of the change types in the table
+var diffMarkers = map[string]string{DiffAdded: "+", DiffRemoved: "-", DiffChanged: "~"}
+
+// diffColors are the colors of the change types in the table
+var diffColors = map[string]Color{DiffAdded: ColorGreen, DiffRemoved: ColorRed, DiffChanged: ColorYellow}
+
+// FieldChange is the change of a field
+type FieldChange struct {
+	Field string      `json:"field" yaml:"field"`
+	Old   interface{} `json:"old" yaml:"old"`
+	New   interface{} `json:"new" yaml:"new"`
+}
+
+// DiffItem is the change of an item, the old or new item is empty if it's added or removed
+type DiffItem struct {
+	Key     string        `json:"key" yaml:"key"`
+	Type    string        `json:"type" yaml:"type"`
+	Old     interface{}   `json:"old,omitempty" yaml:"old,omitempty"`
+	New     interface{}   `json:"new,omitempty" yaml:"new,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// Diff compares the items of two lists which have the same key field, the unchanged items are ignored.
+// The fields are compared if they are given, or all the fields are compared
+func Diff(oldList, newList interface{}, key string, fields ...string) (items []DiffItem, err error) {
+	var oldItems, newItems []reflect.Value
+	if oldItems, err = diffListItems(oldList, key, fields); err != nil {
+		return
+	}
+	if newItems, err = diffListItems(newList, key, fields); err != nil {
+		return
+	}
+
+	var oldKeys, newKeys []string
+	if oldKeys, err = diffKeys(oldItems, key); err != nil {
+		return
+	}
+	if newKeys, err = diffKeys(newItems, key); err != nil {
+		return
+	}
+
+	oldIndexes := map[string]int{}
+	for i, itemKey := range oldKeys {
+		oldIndexes[itemKey] = i
+	}
+	newIndexes := map[string]int{}
+	for i, itemKey := range newKeys {
+		newIndexes[itemKey] = i
+	}
+
+	for i, item := range newItems {
+		itemKey := newKeys[i]
+		index, ok := oldIndexes[itemKey]
+		if !ok {
+			items = append(items, DiffItem{Key: itemKey, Type: DiffAdded, New: item.Interface()})
+			continue
+		}
+
+		var changes []FieldChange
+		if changes, err = diffFields(oldItems[index], item, fields); err != nil {
+			return
+		} else if len(changes) > 0 {
+			items = append(items, DiffItem{Key: itemKey, Type: DiffChanged, Changes: changes})
+		}
+	}
+
+	for i, item := range oldItems {
+		if itemKey := oldKeys[i]; !hasKey(newIndexes, itemKey) {
+			items = append(items, DiffItem{Key: itemKey, Type: DiffRemoved, Old: item.Interface()})
+		}
+	}
+	return
+}
+
+func hasKey(indexes map[string]int, key string) (ok bool) {
+	_, ok = indexes[key]
+	return
+}
+
+// diffKeys returns the keys of the items, an item cannot be identified if its key is not unique
+func diffKeys(items []reflect.Value, key string) (keys []string, err error) {
+	found := map[string]bool{}
+	for _, item := range items {
+		itemKey := ReflectFieldValueAsString(item, key)
+		if found[itemKey] {
+			err = fmt.Errorf("duplicate key %s=%s in the list", key, itemKey)
+			return
+		}
+		found[itemKey] = true
+		keys = append(keys, itemKey)
+	}
+	return
+}
+
+// diffListItems returns the non-nil items of a list, and makes sure the key and fields are valid
+func diffListItems(list interface{}, key string, fields []string) (items []reflect.Value, err error) {
+	value := indirectValue(reflect.ValueOf(list))
+	switch value.Kind() {
+	case reflect.Invalid:
+		return
+	case reflect.Slice, reflect.Array:
+	default:
+		err = fmt.Errorf("not support to diff the kind %s", value.Kind())
+		return
+	}
+
+	for _, field := range append([]string{key}, fields...) {
+		if err = checkFieldPath(value.Type().Elem(), field); err != nil {
+			return
+		}
+	}
+	for i := 0; i < value.Len(); i++ {
+		// the nil items have nothing to compare
+		if item := value.Index(i); indirectValue(item).IsValid() {
+			items = append(items, item)
+		}
+	}
+	return
+}
+
+// diffFields returns the changed fields, all the fields are compared if there's no given field
+func diffFields(oldItem, newItem reflect.Value, fields []string) (changes []FieldChange, err error) {
+	var oldValues, newValues map[string]interface{}
+	var oldNames, names []string
+	if oldValues, oldNames, err = fieldValues(oldItem, fields); err != nil {
+		return
+	}
+	if newValues, names, err = fieldValues(newItem, fields); err != nil {
+		return
+	}
+
+	// the maps might have different keys
+	for _, name := range oldNames {
+		if _, ok := newValues[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(fields) == 0 && indirectValue(newItem).Kind() == reflect.Map {
+		sort.Strings(names)
+	}
+
+	for _, field := range names {
+		oldData, newData := oldValues[field], newValues[field]
+		if !reflect.DeepEqual(oldData, newData) {
+			changes = append(changes, FieldChange{Field: field, Old: oldData, New: newData})
+		}
+	}
+	return
+}
+
+// fieldValues returns the values of the given fields. If there's no given field, they are all the
+// fields of a struct or all the keys of a map
+func fieldValues(item reflect.Value, fields []string) (values map[string]interface{}, names []string, err error) {
+	values = map[string]interface{}{}
+	value := indirectValue(item)
+	if len(fields) == 0 && value.Kind() == reflect.Map {
+		iter := value.MapRange()
+		for iter.Next() {
+			name := fmt.Sprint(iter.Key().Interface())
+			values[name] = valueInterface(iter.Value())
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return
+	}
+
+	if len(fields) == 0 {
+		if fields = FieldPaths(value.Type()); len(fields) == 0 {
+			err = fmt.Errorf("no field to compare in %s", value.Type())
+			return
+		}
+	}
+	for _, field := range fields {
+		var fieldValue reflect.Value
+		if fieldValue, err = ReflectFieldValue(item, field); err != nil {
+			return
+		}
+		values[field] = valueInterface(fieldValue)
+	}
+	names = fields
+	return
+}
+
+func valueInterface(value reflect.Value) interface{} {
+	if value = indirectValue(value); value.IsValid() {
+		return value.Interface()
+	}
+	return nil
+}
+
+// OutputDiff outputs the changes between two lists, the key field identifies the items and the columns
+// are compared. The table shows the changed fields with colors, json and yaml show the patch
+func (o *OutputOption) OutputDiff(oldList, newList interface{}, key string) (err error) {
+	if o.Writer == nil {
+		err = fmt.Errorf("no writer found")
+		return
+	}
+	if err = checkColorMode(o.Color); err != nil {
+		return
+	}
+	if file, ok := o.usePager(); ok {
+		return o.outputWithPager(file, func(option *OutputOption) error {
+			return option.OutputDiff(oldList, newList, key)
+		})
+	}
+
+	var fields []string
+	for _, col := range o.getColumns() {
+		if col.field != "" {
+			fields = append(fields, col.field)
+		}
+	}
+
+	var items []DiffItem
+	if items, err = Diff(oldList, newList, key, fields...); err != nil {
+		return
+	}
+
+	var data []byte
+	switch o.Format {
+	case JSONOutputFormat:
+		data, err = json.MarshalIndent(items, "", "  ")
+	case YAMLOutputFormat:
+		data, err = yaml.Marshal(items)
+	case TableOutputFormat, "":
+		var table Table
+		if table, err = o.getDiffTable(items, key); err == nil {
+			table.Render()
+		}
+	default:
+		err = fmt.Errorf("not support format %s for the diff", o.Format)
+	}
+
+	if err == nil && len(data) > 0 {
+		_, err = o.Writer.Write(data)
+	}
+	return
+}
+
+// getDiffTable returns a table which has a row for each changed field
+func (o *OutputOption) getDiffTable(items []DiffItem, key string) (table Table, err error) {
+	table = CreateTableWithHeader(o.Writer, o.WithoutHeaders)
+	table.ColorMode = o.Color
+	if table.Border, err = GetBorderStyle(o.TableStyle); err != nil {
+		return
+	}
+	table.AddHeader("", o.getHeaderLabel(key), "FIELD", "OLD", "NEW")
+
+	for _, item := range items {
+		var changes []FieldChange
+		if changes, err = o.getDiffChanges(item); err != nil {
+			return
+		}
+
+		color := diffColors[item.Type]
+		for i, change := range changes {
+			marker, itemKey := "", ""
+			if i == 0 {
+				marker, itemKey = diffMarkers[item.Type], item.Key
+			}
+
+			column := column{field: change.Field}
+			for _, col := range o.getColumns() {
+				if col.field == change.Field {
+					column = col
+				}
+			}
+
+			var oldCell, newCell string
+			if oldCell, err = o.renderDiffValue(column, change.Old); err != nil {
+				return
+			}
+			if newCell, err = o.renderDiffValue(column, change.New); err != nil {
+				return
+			}
+
+			row := len(table.Rows)
+			table.AddRow(marker, itemKey, o.getHeaderLabel(change.Field), oldCell, newCell)
+			table.SetCellStyle(row, 0, Style{Foreground: color, Bold: true})
+			table.SetCellStyle(row, 1, Style{Foreground: color})
+			table.SetCellStyle(row, 3, Style{Foreground: ColorRed})
+			table.SetCellStyle(row, 4, Style{Foreground: ColorGreen})
+		}
+	}
+	return
+}
+
+// getDiffChanges returns the changed fields, all the compared fields of the added or removed items
+func (o *OutputOption) getDiffChanges(item DiffItem) (changes []FieldChange, err error) {
+	if item.Type == DiffChanged {
+		changes = item.Changes
+		return
+	}
+
+	obj := item.New
+	if item.Type == DiffRemoved {
+		obj = item.Old
+	}
+	var fields []string
+	if o.Columns != "" {
+		for _, col := range o.getColumns() {
+			fields = append(fields, col.field)
+		}
+	}
+
+	var values map[string]interface{}
+	if values, fields, err = fieldValues(reflect.ValueOf(obj), fields); err != nil {
+		return
+	}
+	for _, field := range fields {
+		change := FieldChange{Field: field}
+		if item.Type == DiffAdded {
+			change.New = values[field]
+		} else {
+			change.Old = values[field]
+		}
+		changes = append(changes, change)
+	}
+	return
+}
+
+// renderDiffValue renders a value with the renderer of the column
+func (o *OutputOption) renderDiffValue(col column, value interface{}) (cell string, err error) {
+	if value == nil {
+		return
+	}
+	return o.renderCell(col, fmt.Sprint(value))
+}
@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff test", func() {
+	type fakeItem struct {
+		Name  string
+		Size  int
+		Phase string
+	}
+
+	var (
+		buffer  *bytes.Buffer
+		opt     *OutputOption
+		oldList []fakeItem
+		newList []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns: "Name,Size:bytes,Phase",
+			Writer:  buffer,
+			Color:   ColorNever,
+		}
+		oldList = []fakeItem{
+			{Name: "a", Size: 1, Phase: "Running"},
+			{Name: "b", Size: 2048, Phase: "Pending"},
+			{Name: "c", Size: 3, Phase: "Running"},
+		}
+		newList = []fakeItem{
+			{Name: "a", Size: 1, Phase: "Running"},
+			{Name: "b", Size: 4096, Phase: "Running"},
+			{Name: "d", Size: 4, Phase: "Pending"},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(SetColorMode(ColorAuto)).To(Succeed())
+	})
+
+	It("diff the items", func() {
+		items, err := Diff(oldList, newList, "Name")
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]DiffItem{{
+			Key:  "b",
+			Type: DiffChanged,
+			Changes: []FieldChange{
+				{Field: "Phase", Old: "Pending", New: "Running"},
+				{Field: "Size", Old: 2048, New: 4096},
+			},
+		}, {
+			Key:  "d",
+			Type: DiffAdded,
+			New:  newList[2],
+		}, {
+			Key:  "c",
+			Type: DiffRemoved,
+			Old:  oldList[2],
+		}}))
+	})
+
+	It("diff the given fields", func() {
+		items, err := Diff(oldList, newList, "Name", "Size")
+		Expect(err).To(BeNil())
+		Expect(items).To(HaveLen(3))
+		Expect(items[0].Changes).To(Equal([]FieldChange{{Field: "Size", Old: 2048, New: 4096}}))
+	})
+
+	It("diff with a nil list", func() {
+		items, err := Diff(nil, newList[:1], "Name")
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]DiffItem{{Key: "a", Type: DiffAdded, New: newList[0]}}))
+	})
+
+	It("diff with nil items", func() {
+		items, err := Diff([]*fakeItem{nil}, []*fakeItem{nil}, "Name")
+		Expect(err).To(BeNil())
+		Expect(items).To(BeEmpty())
+
+		items, err = Diff([]*fakeItem{nil}, []*fakeItem{nil, &newList[0]}, "Name")
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]DiffItem{{Key: "a", Type: DiffAdded, New: &newList[0]}}))
+	})
+
+	It("diff the maps", func() {
+		oldMaps := []map[string]interface{}{{"Name": "a", "Size": 1, "Phase": "Running"}}
+		newMaps := []map[string]interface{}{{"Name": "a", "Size": 2, "Owner": "b"}}
+		items, err := Diff(oldMaps, newMaps, "Name")
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]DiffItem{{
+			Key:  "a",
+			Type: DiffChanged,
+			Changes: []FieldChange{
+				{Field: "Owner", Old: nil, New: "b"},
+				{Field: "Phase", Old: "Running", New: nil},
+				{Field: "Size", Old: 1, New: 2},
+			},
+		}}))
+
+		opt.Columns = ""
+		Expect(opt.OutputDiff(nil, newMaps, "Name")).To(Succeed())
+		Expect(buffer.String()).To(Equal("  Name FIELD OLD NEW\n" +
+			"+ a    Name      a\n" +
+			"       Owner     b\n" +
+			"       Size      2\n"))
+	})
+
+	It("duplicate keys", func() {
+		_, err := Diff(append(oldList, oldList[0]), newList, "Name")
+		Expect(err).To(HaveOccurred())
+
+		_, err = Diff(oldList, append(newList, newList[0]), "Name")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("no field to compare", func() {
+		_, _, err := fieldValues(reflect.ValueOf("fake"), nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("invalid key", func() {
+		_, err := Diff(oldList, newList, "Fake")
+		Expect(err).To(HaveOccurred())
+
+		_, err = Diff("fake", newList, "Name")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("output as a table", func() {
+		err := opt.OutputDiff(oldList, newList, "Name")
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal("  Name FIELD OLD     NEW\n" +
+			"~ b    Size  2KiB    4KiB\n" +
+			"       Phase Pending Running\n" +
+			"+ d    Name          d\n" +
+			"       Size          4B\n" +
+			"       Phase         Pending\n" +
+			"- c    Name  c       \n" +
+			"       Size  3B      \n" +
+			"       Phase Running \n"))
+	})
+
+	It("output as a colored table", func() {
+		opt.Color = ColorAlways
+		opt.Columns = "Name,Phase"
+		err := opt.OutputDiff(oldList[:2], newList[:2], "Name")
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(ContainSubstring(Style{Foreground: ColorYellow, Bold: true}.Render("~")))
+		Expect(buffer.String()).To(ContainSubstring(Style{Foreground: ColorRed}.Render("Pending")))
+		Expect(buffer.String()).To(ContainSubstring(Style{Foreground: ColorGreen}.Render("Running")))
+	})
+
+	It("output as json", func() {
+		opt.Format = JSONOutputFormat
+		opt.Columns = "Name,Phase"
+		err := opt.OutputDiff(oldList[:2], newList[:2], "Name")
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal(`[
+  {
+    "key": "b",
+    "type": "changed",
+    "changes": [
+      {
+        "field": "Phase",
+        "old": "Pending",
+        "new": "Running"
+      }
+    ]
+  }
+]`))
+	})
+
+	It("output as yaml", func() {
+		opt.Format = YAMLOutputFormat
+		err := opt.OutputDiff(oldList[2:], nil, "Name")
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal(`- key: c
+  type: removed
+  old:
+    name: c
+    size: 3
+    phase: Running
+`))
+	})
+
+	It("not supported format", func() {
+		opt.Format = CSVOutputFormat
+		err := opt.OutputDiff(oldList, newList, "Name")
+		Expect(err).To(HaveOccurred())
+	})
+})
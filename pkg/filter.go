@@ -0,0 +1,524 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a parsed filter expression, the supported expressions are like:
+// Name=foo (contains), Name==foo, Name!=foo, Name=~^f.*, Name!~bar, Age>3, Age<=10,
+// Phase in (Running, Pending), !Name==foo, Name==foo || Age>3, (Name==foo || Age>3) && Ready==true
+type Filter struct {
+	raw  string
+	root filterNode
+}
+
+type filterNode interface {
+	match(item reflect.Value) (bool, error)
+	fields() []string
+}
+
+type orNode struct {
+	children []filterNode
+}
+
+type andNode struct {
+	children []filterNode
+}
+
+type notNode struct {
+	child filterNode
+}
+
+type compareNode struct {
+	field  string
+	op     string
+	values []string
+	regex  *regexp.Regexp
+}
+
+// filterParser is a recursive descent parser of the filter expression
+type filterParser struct {
+	text string
+	pos  int
+}
+
+// the operators are sorted by the length, the longer one should be matched at first
+var filterOperators = []string{"==", "!=", "=~", "!~", "<=", ">=", "<", ">", "="}
+
+// ParseFilter parses a filter expression
+func ParseFilter(text string) (filter *Filter, err error) {
+	parser := &filterParser{text: text}
+
+	var root filterNode
+	if root, err = parser.parseOr(); err == nil {
+		if parser.skipSpaces(); parser.pos < len(parser.text) {
+			err = fmt.Errorf("unexpected '%s'", parser.text[parser.pos:])
+		}
+	}
+
+	if err != nil {
+		// keep the plain filters working as before, e.g. Name=a)b
+		var ok bool
+		if root, ok = parsePlainFilter(text); !ok {
+			err = fmt.Errorf("invalid filter '%s', error: %v", text, err)
+			return
+		}
+		err = nil
+	}
+	filter = &Filter{raw: text, root: root}
+	return
+}
+
+// parsePlainFilter parses a filter like Name=foo, the whole text after '=' is the value
+func parsePlainFilter(text string) (node filterNode, ok bool) {
+	index := strings.Index(text, "=")
+	if index <= 0 || strings.HasPrefix(text[index:], "==") || strings.HasPrefix(text[index:], "=~") {
+		return
+	}
+
+	field := text[:index]
+	if strings.ContainsAny(field, " \t!<>()&|") {
+		return
+	} else if _, err := splitFieldPath(field); err != nil {
+		return
+	}
+	node, ok = &compareNode{field: field, op: "=", values: []string{text[index+1:]}}, true
+	return
+}
+
+// Match returns true if the item matches the filter
+func (f *Filter) Match(item reflect.Value) (bool, error) {
+	return f.root.match(item)
+}
+
+// Fields returns all the field paths in the filter
+func (f *Filter) Fields() []string {
+	return f.root.fields()
+}
+
+// String returns the raw text of the filter
+func (f *Filter) String() string {
+	return f.raw
+}
+
+func (p *filterParser) parseOr() (node filterNode, err error) {
+	or := &orNode{}
+	for {
+		var child filterNode
+		if child, err = p.parseAnd(); err != nil {
+			return
+		}
+		or.children = append(or.children, child)
+
+		if !p.consume("||") {
+			break
+		}
+	}
+
+	node = or
+	if len(or.children) == 1 {
+		node = or.children[0]
+	}
+	return
+}
+
+func (p *filterParser) parseAnd() (node filterNode, err error) {
+	and := &andNode{}
+	for {
+		var child filterNode
+		if child, err = p.parseUnary(); err != nil {
+			return
+		}
+		and.children = append(and.children, child)
+
+		if !p.consume("&&") {
+			break
+		}
+	}
+
+	node = and
+	if len(and.children) == 1 {
+		node = and.children[0]
+	}
+	return
+}
+
+func (p *filterParser) parseUnary() (node filterNode, err error) {
+	switch {
+	case p.consume("!"):
+		var child filterNode
+		if child, err = p.parseUnary(); err == nil {
+			node = &notNode{child: child}
+		}
+	case p.consume("("):
+		if node, err = p.parseOr(); err == nil && !p.consume(")") {
+			err = fmt.Errorf("missing ')'")
+		}
+	default:
+		node, err = p.parseComparison()
+	}
+	return
+}
+
+func (p *filterParser) parseComparison() (node filterNode, err error) {
+	p.skipSpaces()
+	field := p.readField()
+	if field == "" {
+		err = fmt.Errorf("field is expected at position %d", p.pos)
+		return
+	}
+	if _, err = splitFieldPath(field); err != nil {
+		return
+	}
+
+	compare := &compareNode{field: field}
+	if p.consumeWord("in") {
+		compare.op = "in"
+		if compare.values, err = p.readList(); err != nil {
+			return
+		}
+		node = compare
+		return
+	}
+
+	for _, op := range filterOperators {
+		if p.consume(op) {
+			compare.op = op
+			break
+		}
+	}
+	if compare.op == "" {
+		err = fmt.Errorf("operator is expected after %s", field)
+		return
+	}
+
+	var value string
+	if value, err = p.readValue(); err != nil {
+		return
+	}
+	compare.values = []string{value}
+
+	if compare.op == "=~" || compare.op == "!~" {
+		if compare.regex, err = regexp.Compile(value); err != nil {
+			return
+		}
+	}
+	node = compare
+	return
+}
+
+// readField reads a field path until an operator or space, the brackets are kept together
+func (p *filterParser) readField() string {
+	start := p.pos
+	for depth := 0; p.pos < len(p.text); p.pos++ {
+		c := p.text[p.pos]
+		switch {
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0 && strings.ContainsRune(" \t=!<>()&|", rune(c)):
+			return p.text[start:p.pos]
+		}
+	}
+	return p.text[start:p.pos]
+}
+
+// readValue reads a quoted string, or the text until '&&', '||' or an unbalanced ')'
+func (p *filterParser) readValue() (value string, err error) {
+	p.skipSpaces()
+	if p.pos < len(p.text) && (p.text[p.pos] == '"' || p.text[p.pos] == '\'') {
+		return p.readQuoted()
+	}
+
+	start := p.pos
+	for depth := 0; p.pos < len(p.text); p.pos++ {
+		rest := p.text[p.pos:]
+		if depth == 0 && (strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||") || rest[0] == ')') {
+			break
+		}
+		switch rest[0] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+	value = strings.TrimSpace(p.text[start:p.pos])
+	return
+}
+
+func (p *filterParser) readQuoted() (value string, err error) {
+	quote := p.text[p.pos]
+	end := strings.IndexByte(p.text[p.pos+1:], quote)
+	if end < 0 {
+		err = fmt.Errorf("unclosed quote at position %d", p.pos)
+		return
+	}
+	value = p.text[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return
+}
+
+// readList reads a list like (a, "b", c)
+func (p *filterParser) readList() (values []string, err error) {
+	if !p.consume("(") {
+		err = fmt.Errorf("'(' is expected after in")
+		return
+	}
+
+	for {
+		p.skipSpaces()
+		var value string
+		if p.pos < len(p.text) && (p.text[p.pos] == '"' || p.text[p.pos] == '\'') {
+			if value, err = p.readQuoted(); err != nil {
+				return
+			}
+		} else {
+			start := p.pos
+			for p.pos < len(p.text) && p.text[p.pos] != ',' && p.text[p.pos] != ')' {
+				p.pos++
+			}
+			value = strings.TrimSpace(p.text[start:p.pos])
+		}
+		values = append(values, value)
+
+		if p.consume(")") {
+			break
+		} else if !p.consume(",") {
+			err = fmt.Errorf("')' is expected for the list")
+			return
+		}
+	}
+	return
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.text) && (p.text[p.pos] == ' ' || p.text[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *filterParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.text[p.pos:], token) {
+		// make sure '!' is not the beginning of '!=' or '!~'
+		if token == "!" && (strings.HasPrefix(p.text[p.pos:], "!=") || strings.HasPrefix(p.text[p.pos:], "!~")) {
+			return false
+		}
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) consumeWord(word string) bool {
+	p.skipSpaces()
+	rest := p.text[p.pos:]
+	if strings.HasPrefix(rest, word) && len(rest) > len(word) && strings.ContainsRune(" \t(", rune(rest[len(word)])) {
+		p.pos += len(word)
+		return true
+	}
+	return false
+}
+
+func (n *orNode) match(item reflect.Value) (ok bool, err error) {
+	for _, child := range n.children {
+		if ok, err = child.match(item); ok || err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (n *orNode) fields() (fields []string) {
+	for _, child := range n.children {
+		fields = append(fields, child.fields()...)
+	}
+	return
+}
+
+func (n *andNode) match(item reflect.Value) (ok bool, err error) {
+	for _, child := range n.children {
+		if ok, err = child.match(item); !ok || err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (n *andNode) fields() (fields []string) {
+	for _, child := range n.children {
+		fields = append(fields, child.fields()...)
+	}
+	return
+}
+
+func (n *notNode) match(item reflect.Value) (ok bool, err error) {
+	ok, err = n.child.match(item)
+	ok = !ok
+	return
+}
+
+func (n *notNode) fields() []string {
+	return n.child.fields()
+}
+
+func (n *compareNode) match(item reflect.Value) (ok bool, err error) {
+	var value reflect.Value
+	if value, err = ReflectFieldValue(item, n.field); err != nil {
+		return
+	}
+	value = indirectValue(value)
+
+	var text string
+	if value.IsValid() {
+		text = fmt.Sprint(value)
+	}
+
+	switch n.op {
+	case "=":
+		ok = strings.Contains(text, n.values[0])
+	case "=~":
+		ok = n.regex.MatchString(text)
+	case "!~":
+		ok = !n.regex.MatchString(text)
+	case "in":
+		for _, expected := range n.values {
+			var result int
+			if result, err = compareFieldValue(value, expected); err != nil {
+				return
+			}
+			if result == 0 {
+				ok = true
+				break
+			}
+		}
+	default:
+		var result int
+		if result, err = compareFieldValue(value, n.values[0]); err != nil {
+			err = fmt.Errorf("cannot compare %s with '%s', error: %v", n.field, n.values[0], err)
+			return
+		}
+
+		switch n.op {
+		case "==":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		}
+	}
+	return
+}
+
+func (n *compareNode) fields() []string {
+	return []string{n.field}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	// the supported layouts of the time in the filter
+	timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+)
+
+// compareFieldValue compares a field value with a text according to the type of the field
+func compareFieldValue(value reflect.Value, text string) (result int, err error) {
+	if !value.IsValid() {
+		return strings.Compare("", text), nil
+	}
+
+	switch {
+	case value.Type() == timeType:
+		var expected time.Time
+		if expected, err = parseTime(text); err == nil {
+			actual := value.Interface().(time.Time)
+			result = compareInt64(actual.UnixNano(), expected.UnixNano())
+		}
+		return
+	case value.Type() == durationType:
+		var expected time.Duration
+		if expected, err = time.ParseDuration(text); err == nil {
+			result = compareInt64(value.Int(), int64(expected))
+		}
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		var expected float64
+		if expected, err = strconv.ParseFloat(text, 64); err == nil {
+			result = compareFloat(numberValue(value), expected)
+		}
+	case reflect.Bool:
+		var expected bool
+		if expected, err = strconv.ParseBool(text); err == nil {
+			result = compareFloat(numberValue(value), numberValue(reflect.ValueOf(expected)))
+		}
+	default:
+		result = strings.Compare(fmt.Sprint(value), text)
+	}
+	return
+}
+
+func parseTime(text string) (result time.Time, err error) {
+	for _, layout := range timeLayouts {
+		if result, err = time.ParseInLocation(layout, text, time.Local); err == nil {
+			return
+		}
+	}
+	err = fmt.Errorf("invalid time '%s', the supported layouts are: %s", text, strings.Join(timeLayouts, ", "))
+	return
+}
+
+// numberValue returns the number of a numeric or bool value
+func numberValue(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Bool:
+		if value.Bool() {
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareFloat(left, right float64) int {
+	switch {
+	case left > right:
+		return 1
+	case left < right:
+		return -1
+	}
+	return 0
+}
+
+func compareInt64(left, right int64) int {
+	switch {
+	case left > right:
+		return 1
+	case left < right:
+		return -1
+	}
+	return 0
+}
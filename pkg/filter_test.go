@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filter test", func() {
+	type fakeItem struct {
+		Name      string
+		Age       int
+		Ratio     float64
+		Ready     bool
+		Timeout   time.Duration
+		CreatedAt time.Time
+		Labels    map[string]string
+	}
+
+	item := reflect.ValueOf(fakeItem{
+		Name:      "alice",
+		Age:       12,
+		Ratio:     0.5,
+		Ready:     true,
+		Timeout:   time.Minute,
+		CreatedAt: time.Date(2021, 3, 1, 10, 0, 0, 0, time.Local),
+		Labels:    map[string]string{"app": "demo"},
+	})
+
+	DescribeTable("match",
+		func(expression string, expected bool) {
+			filter, err := ParseFilter(expression)
+			Expect(err).NotTo(HaveOccurred())
+			ok, err := filter.Match(item)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("contains", "Name=lic", true),
+		Entry("equal", "Name==alice", true),
+		Entry("equal with quote", `Name == "alic"`, false),
+		Entry("not equal", "Name!=bob", true),
+		Entry("regex", "Name=~^a.*e$", true),
+		Entry("regex with group", "Name=~^(bob|alice)$", true),
+		Entry("not regex", "Name!~^a", false),
+		Entry("numeric", "Age>9", true),
+		Entry("numeric instead of lexical", "Age<9", false),
+		Entry("float", "Ratio<=0.5", true),
+		Entry("bool", "Ready==true", true),
+		Entry("duration", "Timeout>30s", true),
+		Entry("date", "CreatedAt>=2021-03-01", true),
+		Entry("time", "CreatedAt<2021-03-01 09:00:00", false),
+		Entry("in", "Name in (bob, alice)", true),
+		Entry("not in", "!Name in (bob, 'tom')", true),
+		Entry("nested field", "Labels.app==demo", true),
+		Entry("or", "Name==bob || Age>10", true),
+		Entry("and", "Name==alice && Age>20", false),
+		Entry("group with negation", "!(Name==bob || Age>20) && Ready==true", true),
+	)
+
+	DescribeTable("invalid expression",
+		func(expression string) {
+			_, err := ParseFilter(expression)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no operator", "Name"),
+		Entry("no field", "==alice"),
+		Entry("invalid regex", "Name=~(a"),
+		Entry("unclosed group", "(Name==a || Age>3"),
+		Entry("unclosed list", "Name in (a, b"),
+		Entry("unclosed quote", `Name=="a`),
+	)
+
+	It("plain filter", func() {
+		filter, err := ParseFilter("Name=a)b")
+		Expect(err).NotTo(HaveOccurred())
+		ok, err := filter.Match(reflect.ValueOf(fakeItem{Name: "xa)b"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		filter, err = ParseFilter("Name=a && b")
+		Expect(err).NotTo(HaveOccurred())
+		ok, err = filter.Match(reflect.ValueOf(fakeItem{Name: "a && b"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("type mismatch", func() {
+		filter, err := ParseFilter("Age>abc")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = filter.Match(item)
+		Expect(err).To(HaveOccurred())
+		Expect(filter.Fields()).To(Equal([]string{"Age"}))
+	})
+
+	It("output with filters", func() {
+		buffer := new(bytes.Buffer)
+		opt := &OutputOption{
+			Columns: "Name,Age",
+			Writer:  buffer,
+			Filter:  []string{"Age>=10", "Name!=tom"},
+		}
+		Expect(opt.OutputV2([]fakeItem{{Name: "alice", Age: 12}, {Name: "tom", Age: 20}, {Name: "bob", Age: 9}})).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name  Age\nalice 12\n"))
+
+		opt.Filter = []string{"Name"}
+		Expect(opt.OutputV2([]fakeItem{})).NotTo(Succeed())
+		opt.Filter = []string{"Fake==a"}
+		Expect(opt.OutputV2([]fakeItem{})).NotTo(Succeed())
+	})
+
+	It("filter with errors", func() {
+		opt := &OutputOption{Filter: []string{"Age>=10"}}
+		items := []fakeItem{{Name: "alice", Age: 12}, {Name: "bob", Age: 9}}
+		result, err := opt.ListFilterWithError(items)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(items[:1]))
+
+		ok, err := opt.MatchWithError(item)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		opt.Filter = []string{"Name"}
+		_, err = opt.ListFilterWithError(items)
+		Expect(err).To(HaveOccurred())
+		_, err = opt.MatchWithError(item)
+		Expect(err).To(HaveOccurred())
+	})
+	It("deprecated filters ignore the invalid ones", func() {
+		opt := &OutputOption{Filter: []string{"Name", "Age>=10"}}
+		items := []fakeItem{{Name: "alice", Age: 12}, {Name: "bob", Age: 9}}
+		Expect(opt.ListFilter(items)).To(Equal(items[:1]))
+		Expect(opt.Match(reflect.ValueOf(items[0]))).To(BeTrue())
+		Expect(opt.Match(reflect.ValueOf(items[1]))).To(BeFalse())
+
+		// the item does not match if the filter cannot compare it
+		opt.Filter = []string{"Age>abc"}
+		Expect(opt.ListFilter(items)).To(BeEmpty())
+		Expect(opt.Match(reflect.ValueOf(items[0]))).To(BeFalse())
+	})
+})
@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxFieldDepth is the max depth of the nested fields for the completion
+const maxFieldDepth = 3
+
+// OutputFormats contains all the supported output formats, the ones end with '=' require an argument
+var OutputFormats = []string{
+	TableOutputFormat, JSONOutputFormat, YAMLOutputFormat,
+	CSVOutputFormat, TSVOutputFormat, MarkdownOutputFormat,
+	GoTemplateOutputFormat + "=", GoTemplateFileOutputFormat + "=",
+	JSONPathOutputFormat + "=", CustomColumnsOutputFormat + "=",
+}
+
+// RegisterFlagCompletion registers the completion functions of the columns, filter, sort-by, group-by and aggregate flags,
+// obj is the list of the items or an item, the field names come from its type
+func (o *OutputOption) RegisterFlagCompletion(cmd *cobra.Command, obj interface{}) {
+	fields := FieldPaths(reflect.TypeOf(obj))
+
+	completions := map[string]func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective){
+		"columns": func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeList(fields, toComplete, ""), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+		"sort-by": func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeList(fields, toComplete, "-"), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+		"group-by": func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeList(fields, toComplete, ""), cobra.ShellCompDirectiveNoFileComp
+		},
+		"aggregate": func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			result := make([]string, 0, len(fields))
+			for _, field := range fields {
+				for _, fn := range AggregateFuncs {
+					result = append(result, field+":"+fn)
+				}
+			}
+			return completeList(result, toComplete, ""), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+		"filter": func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			result := make([]string, 0, len(fields))
+			for _, field := range fields {
+				if strings.HasPrefix(field, toComplete) {
+					result = append(result, field+"=")
+				}
+			}
+			return result, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+	}
+
+	for name, completion := range completions {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		if err := cmd.RegisterFlagCompletionFunc(name, completion); err != nil {
+			cmd.PrintErrf("register flag %s completion for sub-command %s failed %#v\n", name, cmd.Name(), err)
+		}
+	}
+}
+
+// FieldPaths returns the paths of all the fields of a type, e.g. Name, Spec.Owner.Name
+func FieldPaths(t reflect.Type) (paths []string) {
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	paths = collectFieldPaths(t, "", 1)
+	sort.Strings(paths)
+	return
+}
+
+func collectFieldPaths(t reflect.Type, prefix string, depth int) (paths []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported field
+			continue
+		}
+
+		path := prefix + field.Name
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType && depth < maxFieldDepth {
+			paths = append(paths, collectFieldPaths(fieldType, path+".", depth+1)...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	return
+}
+
+// completeList completes the last item of a comma-separated list, the chosen items will be excluded
+func completeList(candidates []string, toComplete, optionalPrefix string) (result []string) {
+	var chosen []string
+	current := toComplete
+	if index := strings.LastIndex(toComplete, ","); index >= 0 {
+		chosen = strings.Split(toComplete[:index], ",")
+		current = toComplete[index+1:]
+	}
+	prefix := strings.TrimSuffix(toComplete, current)
+
+	itemPrefix := ""
+	if optionalPrefix != "" && strings.HasPrefix(current, optionalPrefix) {
+		itemPrefix = optionalPrefix
+		current = strings.TrimPrefix(current, optionalPrefix)
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate, current) || containsItem(chosen, candidate, optionalPrefix) {
+			continue
+		}
+		result = append(result, prefix+itemPrefix+candidate)
+	}
+	return
+}
+
+func containsItem(items []string, target, optionalPrefix string) bool {
+	for _, item := range items {
+		if item == target || (optionalPrefix != "" && strings.TrimPrefix(item, optionalPrefix) == target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flag completion test", func() {
+	type fakeOwner struct {
+		Name string
+	}
+	type fakeItem struct {
+		Name      string
+		Age       int
+		CreatedAt time.Time
+		Owner     *fakeOwner
+		internal  string
+	}
+
+	complete := func(args ...string) []string {
+		opt := &OutputOption{}
+		root := &cobra.Command{Use: "root"}
+		cmd := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+		root.AddCommand(cmd)
+		opt.SetFlagWithHeaders(cmd, "Name")
+		opt.RegisterFlagCompletion(cmd, []fakeItem{})
+
+		buffer := new(bytes.Buffer)
+		root.SetOut(buffer)
+		root.SetArgs(append([]string{cobra.ShellCompRequestCmd, "list"}, args...))
+		Expect(root.Execute()).To(Succeed())
+
+		// the last line is the directive
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		return lines[:len(lines)-1]
+	}
+
+	It("field paths", func() {
+		Expect(FieldPaths(reflect.TypeOf([]fakeItem{}))).To(Equal([]string{"Age", "CreatedAt", "Name", "Owner.Name"}))
+		Expect(FieldPaths(nil)).To(BeEmpty())
+	})
+
+	It("columns", func() {
+		Expect(complete("--columns", "")).To(Equal([]string{"Age", "CreatedAt", "Name", "Owner.Name"}))
+		Expect(complete("--columns", "Name,")).To(Equal([]string{"Name,Age", "Name,CreatedAt", "Name,Owner.Name"}))
+		Expect(complete("--columns", "Name,O")).To(Equal([]string{"Name,Owner.Name"}))
+	})
+
+	It("sort-by", func() {
+		Expect(complete("--sort-by=-A")).To(Equal([]string{"-Age"}))
+		Expect(complete("--sort-by=-Age,N")).To(Equal([]string{"-Age,Name"}))
+	})
+
+	It("filter", func() {
+		Expect(complete("--filter", "N")).To(Equal([]string{"Name="}))
+	})
+
+	It("output", func() {
+		Expect(complete("-o", "")).To(Equal(OutputFormats))
+	})
+	It("opt-in flags", func() {
+		opt := &OutputOption{}
+		cmd := &cobra.Command{Use: "list"}
+		// the command might have its own flags which have the same names
+		cmd.Flags().String("color", "", "")
+		opt.SetFlagWithHeaders(cmd, "Name")
+		for _, name := range []string{"table-style", "interactive", "no-pager"} {
+			Expect(cmd.Flags().Lookup(name)).To(BeNil())
+		}
+
+		cmd = &cobra.Command{Use: "list"}
+		opt.SetFlag(cmd)
+		opt.SetColorFlag(cmd)
+		opt.SetTableStyleFlag(cmd)
+		opt.SetInteractiveFlag(cmd)
+		opt.SetPagerFlag(cmd)
+		Expect(cmd.Flags().Lookup("color").DefValue).To(Equal(ColorAuto))
+		for _, name := range []string{"table-style", "interactive", "no-pager"} {
+			Expect(cmd.Flags().Lookup(name)).NotTo(BeNil())
+		}
+		Expect(opt.Pager).To(BeTrue())
+	})
+})
@@ -0,0 +1,356 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed JSONPath template, e.g. {range [*]}{.name}{"\t"}{.age}{"\n"}{end}
+type JSONPath struct {
+	nodes []jsonPathNode
+
+	// AllowMissingKeys outputs nothing instead of an error when a key is missing
+	AllowMissingKeys bool
+}
+
+type jsonPathNode struct {
+	text     string
+	isText   bool
+	segments []pathSegment
+	// children is the body of a range node
+	children []jsonPathNode
+}
+
+type pathSegment struct {
+	field    string
+	wildcard bool
+	isIndex  bool
+	isSlice  bool
+	index    int
+	// the end of a slice, it's nil if the end is not specified
+	end *int
+}
+
+// ParseJSONPath parses a JSONPath template
+func ParseJSONPath(text string) (path *JSONPath, err error) {
+	var nodes []jsonPathNode
+	if nodes, _, err = parseJSONPathNodes(text, false); err != nil {
+		err = fmt.Errorf("invalid jsonpath '%s', error: %v", text, err)
+		return
+	}
+	path = &JSONPath{nodes: nodes}
+	return
+}
+
+// Execute applies the template to the data, the data will be converted as the JSON object at first
+func (j *JSONPath) Execute(writer io.Writer, obj interface{}) (err error) {
+	var data interface{}
+	if data, err = toJSONObject(obj); err == nil {
+		err = j.execute(writer, j.nodes, data)
+	}
+	return
+}
+
+func (j *JSONPath) execute(writer io.Writer, nodes []jsonPathNode, data interface{}) (err error) {
+	for _, node := range nodes {
+		if node.isText {
+			if _, err = io.WriteString(writer, node.text); err != nil {
+				return
+			}
+			continue
+		}
+
+		var values []interface{}
+		if values, err = j.evaluate(node.segments, data); err != nil {
+			return
+		}
+
+		if node.children != nil {
+			for _, value := range values {
+				if err = j.execute(writer, node.children, value); err != nil {
+					return
+				}
+			}
+			continue
+		}
+
+		texts := make([]string, 0, len(values))
+		for _, value := range values {
+			texts = append(texts, jsonValueAsString(value))
+		}
+		if _, err = io.WriteString(writer, strings.Join(texts, " ")); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (j *JSONPath) evaluate(segments []pathSegment, data interface{}) (values []interface{}, err error) {
+	values = []interface{}{data}
+	for _, segment := range segments {
+		var next []interface{}
+		for _, value := range values {
+			var found []interface{}
+			if found, err = segment.evaluate(value); err != nil {
+				if j.AllowMissingKeys {
+					err = nil
+					continue
+				}
+				return
+			}
+			next = append(next, found...)
+		}
+		values = next
+	}
+	return
+}
+
+func (s pathSegment) evaluate(data interface{}) (values []interface{}, err error) {
+	switch item := data.(type) {
+	case map[string]interface{}:
+		switch {
+		case s.wildcard:
+			keys := make([]string, 0, len(item))
+			for key := range item {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				values = append(values, item[key])
+			}
+		case s.isIndex || s.isSlice:
+			err = fmt.Errorf("cannot index an object with [%d]", s.index)
+		default:
+			value, ok := item[s.field]
+			if !ok {
+				err = fmt.Errorf("%s is not found", s.field)
+				return
+			}
+			values = append(values, value)
+		}
+	case []interface{}:
+		switch {
+		case s.wildcard:
+			values = append(values, item...)
+		case s.isIndex:
+			index := s.index
+			if index < 0 {
+				index += len(item)
+			}
+			if index < 0 || index >= len(item) {
+				err = fmt.Errorf("index [%d] is out of range", s.index)
+				return
+			}
+			values = append(values, item[index])
+		case s.isSlice:
+			start, end := s.index, len(item)
+			if s.end != nil {
+				end = *s.end
+			}
+			if start < 0 {
+				start += len(item)
+			}
+			if end < 0 {
+				end += len(item)
+			}
+			for i := start; i < end && i < len(item); i++ {
+				if i >= 0 {
+					values = append(values, item[i])
+				}
+			}
+		default:
+			err = fmt.Errorf("cannot find %s from an array", s.field)
+		}
+	default:
+		switch {
+		case s.wildcard || (s.isSlice && data == nil):
+		case s.isIndex:
+			err = fmt.Errorf("index [%d] is out of range", s.index)
+		default:
+			err = fmt.Errorf("%s is not found", s.field)
+		}
+	}
+	return
+}
+
+// parseJSONPathNodes parses the nodes until the end of the text or an {end}, returns the rest text after the {end}
+func parseJSONPathNodes(text string, inRange bool) (nodes []jsonPathNode, rest string, err error) {
+	for len(text) > 0 {
+		start := strings.Index(text, "{")
+		if start < 0 {
+			nodes = append(nodes, jsonPathNode{text: text, isText: true})
+			text = ""
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, jsonPathNode{text: text[:start], isText: true})
+		}
+
+		end := findClosingBrace(text, start)
+		if end < 0 {
+			err = fmt.Errorf("unclosed action")
+			return
+		}
+		action := strings.TrimSpace(text[start+1 : end])
+		text = text[end+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				err = fmt.Errorf("unexpected {end}")
+			}
+			rest = text
+			return
+		case strings.HasPrefix(action, "range "):
+			node := jsonPathNode{children: []jsonPathNode{}}
+			if node.segments, err = parsePathSegments(strings.TrimSpace(strings.TrimPrefix(action, "range "))); err != nil {
+				return
+			}
+
+			var children []jsonPathNode
+			if children, text, err = parseJSONPathNodes(text, true); err != nil {
+				return
+			}
+			node.children = append(node.children, children...)
+			nodes = append(nodes, node)
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+			var literal string
+			if literal, err = strconv.Unquote(`"` + strings.Trim(action, `"'`) + `"`); err != nil {
+				return
+			}
+			nodes = append(nodes, jsonPathNode{text: literal, isText: true})
+		default:
+			node := jsonPathNode{}
+			if node.segments, err = parsePathSegments(action); err != nil {
+				return
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	if inRange {
+		err = fmt.Errorf("no {end} for the range")
+	}
+	return
+}
+
+func findClosingBrace(text string, start int) int {
+	var quote rune
+	for i, r := range text[start+1:] {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '}':
+			return start + 1 + i
+		}
+	}
+	return -1
+}
+
+// parsePathSegments parses a path like $.items[*].metadata['name']
+func parsePathSegments(path string) (segments []pathSegment, err error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), "@")
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			if name := path[:end]; name == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else if name != "" {
+				segments = append(segments, pathSegment{field: name})
+			}
+			path = path[end:]
+		case '[':
+			end := strings.Index(path, "]")
+			if end < 0 {
+				err = fmt.Errorf("unclosed bracket in %s", path)
+				return
+			}
+			var segment pathSegment
+			if segment, err = parseBracket(path[1:end]); err != nil {
+				return
+			}
+			segments = append(segments, segment)
+			path = path[end+1:]
+		default:
+			// the first field without the dot, e.g. items[0]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			segments = append(segments, pathSegment{field: path[:end]})
+			path = path[end:]
+		}
+	}
+	return
+}
+
+func parseBracket(content string) (segment pathSegment, err error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		segment.wildcard = true
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		segment.field = strings.Trim(content, `'"`)
+	case strings.Contains(content, ":"):
+		segment.isSlice = true
+		items := strings.SplitN(content, ":", 2)
+		if items[0] != "" {
+			if segment.index, err = strconv.Atoi(items[0]); err != nil {
+				return
+			}
+		}
+		if items[1] != "" {
+			var end int
+			if end, err = strconv.Atoi(items[1]); err != nil {
+				return
+			}
+			segment.end = &end
+		}
+	default:
+		segment.isIndex = true
+		if segment.index, err = strconv.Atoi(content); err != nil {
+			err = fmt.Errorf("invalid index [%s]", content)
+		}
+	}
+	return
+}
+
+// toJSONObject converts an object to the generic JSON object, e.g. map[string]interface{}
+func toJSONObject(obj interface{}) (data interface{}, err error) {
+	var raw []byte
+	if raw, err = json.Marshal(obj); err != nil {
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	err = decoder.Decode(&data)
+	return
+}
+
+func jsonValueAsString(value interface{}) string {
+	switch item := value.(type) {
+	case string:
+		return item
+	case nil:
+		return ""
+	case json.Number, bool:
+		return fmt.Sprint(item)
+	default:
+		data, _ := json.Marshal(item)
+		return string(data)
+	}
+}
@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// OverflowTruncate cuts the end of a long cell with an ellipsis
+	OverflowTruncate = 0
+	// OverflowTruncateMiddle cuts the middle of a long cell with an ellipsis
+	OverflowTruncateMiddle = 1
+	// OverflowWrap wraps a long cell into multiple lines
+	OverflowWrap = 2
+)
+
+// minColumnWidth is the min width of a column when shrinking the table to fit the terminal
+const minColumnWidth = 4
+
+const ellipsis = "…"
+
+// terminalBuffer buffers the output for a terminal, the width and color are decided by the terminal
+type terminalBuffer struct {
+	bytes.Buffer
+	terminal io.Writer
+}
+
+// terminalFile returns the file which the writer writes to, it's the terminal of a terminal buffer
+func terminalFile(out io.Writer) (file *os.File, ok bool) {
+	switch writer := out.(type) {
+	case *os.File:
+		return writer, true
+	case *terminalBuffer:
+		return terminalFile(writer.terminal)
+	}
+	return
+}
+
+// TerminalWidth returns the width of the terminal which the writer writes to, it's zero if it's not a terminal
+func TerminalWidth(out io.Writer) (width int) {
+	file, ok := terminalFile(out)
+	if !ok || !isTerminal(file) {
+		return
+	}
+
+	if width, _ = terminalSize(file); width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	return
+}
+
+// TerminalHeight returns the height of the terminal which the writer writes to, it's zero if it's not a terminal
+func TerminalHeight(out io.Writer) (height int) {
+	file, ok := terminalFile(out)
+	if !ok || !isTerminal(file) {
+		return
+	}
+
+	if _, height = terminalSize(file); height <= 0 {
+		height, _ = strconv.Atoi(os.Getenv("LINES"))
+	}
+	return
+}
+
+// TruncateMiddle cuts the middle of the text to the width with an ellipsis
+func TruncateMiddle(text string, width int) string {
+	if width <= 0 || Lenf(text) <= width {
+		return text
+	}
+
+	clusters := splitGraphemes(StripANSI(text))
+	tailWidth := (width - 1) / 2
+	var tail string
+	for i := len(clusters) - 1; i >= 0 && Lenf(clusters[i]+tail) <= tailWidth; i-- {
+		tail = clusters[i] + tail
+	}
+	return cutWidth(text, width-1-tailWidth) + ellipsis + tail
+}
+
+// Wrap breaks the text into lines which are not wider than the width, a word longer than the width is broken as well
+func Wrap(text string, width int) (lines []string) {
+	if width <= 0 {
+		return []string{text}
+	}
+
+	text = StripANSI(text)
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case Lenf(line)+1+Lenf(word) <= width:
+				line += " " + word
+				continue
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+
+			for Lenf(line) > width {
+				part := cutWidth(line, width)
+				if part == "" {
+					// the width is less than a wide character
+					part = splitGraphemes(line)[0]
+				}
+				lines = append(lines, part)
+				line = line[len(part):]
+			}
+		}
+		lines = append(lines, line)
+	}
+	return
+}
+
+// cutWidth returns the longest prefix of the text which is not wider than the width
+func cutWidth(text string, width int) string {
+	text = StripANSI(text)
+
+	var count, length int
+	for _, cluster := range splitGraphemes(text) {
+		if count += graphemeWidth(cluster); count > width {
+			break
+		}
+		length += len(cluster)
+	}
+	return text[:length]
+}
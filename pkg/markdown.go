@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// markdownEscaper escapes the characters which break a markdown table
+var markdownEscaper = strings.NewReplacer("\\", "\\\\", "|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func (o *OutputOption) outputMarkdown(obj interface{}) (err error) {
+	write := func(record []string) error {
+		cells := make([]string, 0, len(record))
+		for _, cell := range record {
+			cells = append(cells, markdownEscaper.Replace(StripANSI(cell)))
+		}
+		_, err := fmt.Fprintf(o.Writer, "| %s |\n", strings.Join(cells, " | "))
+		return err
+	}
+
+	// a markdown table is invalid without the header row, so the headers are always written
+	headers := o.GetHeaders()
+	separators := make([]string, len(headers))
+	for i := range separators {
+		separators[i] = "---"
+	}
+
+	if err = write(headers); err != nil {
+		return
+	}
+	if _, err = fmt.Fprintf(o.Writer, "|%s|\n", strings.Join(separators, "|")); err != nil {
+		return
+	}
+
+	items := reflect.ValueOf(obj)
+	for i := 0; i < items.Len(); i++ {
+		var line []string
+		if line, err = o.getLine(items.Index(i)); err != nil {
+			return
+		}
+		if err = write(line); err != nil {
+			return
+		}
+	}
+	return
+}
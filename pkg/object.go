@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// keyValue is a row of the key/value table
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+// prepareObject converts a single object or a map to the data which could be rendered in the format
+func (o *OutputOption) prepareObject(value reflect.Value, format string) (option *OutputOption, obj interface{}, err error) {
+	option, obj = o, value.Interface()
+
+	switch format {
+	case JSONOutputFormat, YAMLOutputFormat, GoTemplateOutputFormat, GoTemplateFileOutputFormat, JSONPathOutputFormat:
+		// the object could be rendered as it is
+		return
+	}
+
+	switch {
+	case value.Kind() == reflect.Map && isStructType(value.Type().Elem()):
+		// each value is a row, order by the keys
+		obj, err = o.prepareList(sortedMapValues(value).Interface(), format)
+	case value.Kind() == reflect.Struct && format != TableOutputFormat && format != "":
+		list := reflect.Append(reflect.MakeSlice(reflect.SliceOf(value.Type()), 0, 1), value)
+		obj, err = o.prepareList(list.Interface(), format)
+	default:
+		var rows []keyValue
+		if rows, err = o.getKeyValues(value); err != nil {
+			return
+		}
+
+		option = &OutputOption{
+			Format:  o.Format,
+			Columns: "Key,Value",
+			// there's no header of the key/value table
+			WithoutHeaders: o.WithoutHeaders || format == TableOutputFormat || format == "",
+			Writer:         o.Writer,
+			TableStyle:     o.TableStyle,
+		}
+		obj = rows
+	}
+	return
+}
+
+// getKeyValues returns the columns and values of a struct, or the keys and values of a map
+func (o *OutputOption) getKeyValues(value reflect.Value) (rows []keyValue, err error) {
+	if value.Kind() == reflect.Struct {
+		if err = o.checkFields(reflect.MakeSlice(reflect.SliceOf(value.Type()), 0, 0).Interface(), TableOutputFormat); err != nil {
+			return
+		}
+
+		var line []string
+		if line, err = o.getLine(value); err != nil {
+			return
+		}
+		for i, header := range o.GetHeaders() {
+			rows = append(rows, keyValue{Key: header, Value: line[i]})
+		}
+		return
+	}
+
+	for _, key := range sortedMapKeys(value) {
+		var cell string
+		if item := value.MapIndex(key); item.IsValid() {
+			cell = fmt.Sprint(item)
+		}
+		rows = append(rows, keyValue{Key: fmt.Sprint(key), Value: cell})
+	}
+	return
+}
+
+// sortedMapValues returns the values of a map as a slice, order by the keys
+func sortedMapValues(value reflect.Value) reflect.Value {
+	list := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), 0, value.Len())
+	for _, key := range sortedMapKeys(value) {
+		list = reflect.Append(list, value.MapIndex(key))
+	}
+	return list
+}
+
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func isStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
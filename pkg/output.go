@@ -6,8 +6,12 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 	"io"
+	"io/ioutil"
+	"os"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 )
 
 // OutputOption represent the format of output
@@ -17,14 +21,57 @@ type OutputOption struct {
 	Columns        string
 	WithoutHeaders bool
 	Filter         []string
+	SortBy         []string
+	Limit          int
+	Offset         int
+	// Color is the color mode, it should be one of auto, always and never
+	Color string
+	// TableStyle is the border style name of the table, see also BorderStyles
+	TableStyle string
+	// Aggregates are the footer functions of the columns, e.g. Size:sum, Name:count
+	Aggregates []string
+	// GroupBy is the field which groups the rows of the table
+	GroupBy string
+	// Interactive shows the table in the interactive viewer if it's taller than the terminal
+	Interactive bool
+	// Watch keeps fetching and outputting the items, see also OutputWatch
+	Watch bool
+	// WatchInterval is the interval between the fetches in the watch mode
+	WatchInterval time.Duration
+	// WatchKey is the field which identifies the items in the watch mode, it's the first column if it's empty
+	WatchKey string
+	// WatchEvents wraps the items with the event types in json or yaml, see also WatchEvent.
+	// The removed items are only printed with the events
+	WatchEvents bool
+	// Pager pipes the output through the pager if it's taller than the terminal, see also GetPagerCommand
+	Pager bool
+	// NoPager disables the pager, it comes from the flag --no-pager
+	NoPager bool
 
 	Writer        io.Writer
 	CellRenderMap map[string]RenderCell
+	// HeaderLabels are the labels of the table headers, the key is the column
+	HeaderLabels map[string]string
+	// ColumnAligns are the alignments of the table columns, the key is the column
+	ColumnAligns map[string]int
+	// WideColumns are the extra columns of the wide format
+	WideColumns string
+	// ColumnPresets are the named column sets, the key is the name which could be used as the output format
+	ColumnPresets map[string]string
+
+	// StreamWindow is the count of the rows which are used to compute the column widths in the stream mode
+	StreamWindow int
 }
 
 // RenderCell render a specific cell in a table
 type RenderCell = func(string) string
 
+// column is a column of the table, e.g. Size:bytes, Description:truncate=20
+type column struct {
+	field    string
+	renderer string
+}
+
 // FormatOutput is the interface of format output
 type FormatOutput interface {
 	Output(obj interface{}, format string) (data []byte, err error)
@@ -37,6 +84,22 @@ const (
 	YAMLOutputFormat string = "yaml"
 	// TableOutputFormat is the format of table
 	TableOutputFormat string = "table"
+	// GoTemplateOutputFormat is the format of golang template, e.g. go-template={{.Name}}
+	GoTemplateOutputFormat string = "go-template"
+	// GoTemplateFileOutputFormat is the format of golang template file, e.g. go-template-file=path
+	GoTemplateFileOutputFormat string = "go-template-file"
+	// JSONPathOutputFormat is the format of JSONPath, e.g. jsonpath={[*].Name}
+	JSONPathOutputFormat string = "jsonpath"
+	// CustomColumnsOutputFormat is the format of custom columns, e.g. custom-columns=NAME:.Name,AGE:.Age
+	CustomColumnsOutputFormat string = "custom-columns"
+	// CSVOutputFormat is the format of comma-separated values
+	CSVOutputFormat string = "csv"
+	// TSVOutputFormat is the format of tab-separated values
+	TSVOutputFormat string = "tsv"
+	// MarkdownOutputFormat is the format of markdown table
+	MarkdownOutputFormat string = "markdown"
+	// WideOutputFormat is the format of table with the extra columns
+	WideOutputFormat string = "wide"
 )
 
 // Output print the object into byte array
@@ -60,27 +123,154 @@ func (o *OutputOption) OutputV2(obj interface{}) (err error) {
 	}
 
 	if len(o.Columns) == 0 {
-		err = fmt.Errorf("no columns found")
+		// the columns could come from the struct tags, the maps of the copy are cloned to keep the option unchanged
+		option := *o
+		option.HeaderLabels = make(map[string]string, len(o.HeaderLabels))
+		for field, label := range o.HeaderLabels {
+			option.HeaderLabels[field] = label
+		}
+		option.ColumnAligns = make(map[string]int, len(o.ColumnAligns))
+		for field, align := range o.ColumnAligns {
+			option.ColumnAligns[field] = align
+		}
+		if err = option.SetTableColumns(obj); err == nil && len(option.Columns) == 0 {
+			err = fmt.Errorf("no columns found")
+		}
+		if err == nil {
+			err = option.OutputV2(obj)
+		}
+		return
+	}
+
+	if file, ok := o.usePager(); ok {
+		return o.outputWithPager(file, func(option *OutputOption) error {
+			return option.OutputV2(obj)
+		})
+	}
+
+	if err = checkColorMode(o.Color); err != nil {
+		return
+	}
+
+	if columns, ok := o.getPresetColumns(); ok {
+		option := *o
+		option.Format = TableOutputFormat
+		option.Columns = columns
+		return option.OutputV2(obj)
+	}
+
+	format, arg := parseOutputFormat(o.Format)
+	value := indirectValue(reflect.ValueOf(obj))
+	if listType := reflect.TypeOf(obj); !value.IsValid() && listType != nil {
+		// a nil pointer of a list is an empty list
+		for listType.Kind() == reflect.Ptr {
+			listType = listType.Elem()
+		}
+		if listType.Kind() == reflect.Slice {
+			value = reflect.MakeSlice(listType, 0, 0)
+		}
+	}
+	option := o
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		obj, err = o.prepareList(value.Interface(), format)
+	case reflect.Struct, reflect.Map:
+		option, obj, err = o.prepareObject(value, format)
+	case reflect.Invalid:
+		err = fmt.Errorf("no object found")
+	default:
+		err = fmt.Errorf("not support to output the kind %s", value.Kind())
+	}
+
+	if err == nil {
+		err = option.render(obj, format, arg)
+	}
+	return
+}
+
+// prepareList checks, filters, sorts and pages the list
+func (o *OutputOption) prepareList(obj interface{}, format string) (result interface{}, err error) {
+	if err = o.checkFields(obj, format); err != nil {
 		return
 	}
 
 	//cmd.logger.Debug("start to output", zap.Any("filter", o.Filter))
-	obj = o.ListFilter(obj)
+	if obj, err = o.filterList(obj); err != nil {
+		return
+	}
+	if obj, err = o.sortList(obj); err != nil {
+		return
+	}
+	if obj, err = o.groupList(obj, format); err != nil {
+		return
+	}
+	result = o.pageList(obj)
+	return
+}
 
+// render outputs the data in the format
+func (o *OutputOption) render(obj interface{}, format, arg string) (err error) {
 	var data []byte
-	switch o.Format {
+	switch format {
+	case GoTemplateOutputFormat, GoTemplateFileOutputFormat:
+		err = o.outputGoTemplate(obj, format, arg)
+	case JSONPathOutputFormat:
+		err = o.outputJSONPath(obj, arg)
+	case CustomColumnsOutputFormat:
+		err = o.outputCustomColumns(obj, arg)
+	case CSVOutputFormat, TSVOutputFormat:
+		err = o.outputSeparatedValues(obj, format)
+	case MarkdownOutputFormat:
+		err = o.outputMarkdown(obj)
 	case JSONOutputFormat:
 		data, err = json.MarshalIndent(obj, "", "  ")
 	case YAMLOutputFormat:
 		data, err = yaml.Marshal(obj)
 	case TableOutputFormat, "":
 		table := CreateTableWithHeader(o.Writer, o.WithoutHeaders)
-		table.AddHeader(strings.Split(o.Columns, ",")...)
+		table.ColorMode = o.Color
+		if table.Border, err = GetBorderStyle(o.TableStyle); err != nil {
+			return
+		}
+		table.AddHeader(o.GetHeaders()...)
+		for i, col := range o.getColumns() {
+			if align, ok := o.ColumnAligns[col.field]; ok {
+				table.SetColumnAlign(i, align)
+			}
+		}
 		items := reflect.ValueOf(obj)
+		var group string
 		for i := 0; i < items.Len(); i++ {
-			table.AddRow(o.GetLine(items.Index(i))...)
+			if o.GroupBy != "" {
+				var current string
+				if current, err = ReflectFieldValueAsStringWithError(items.Index(i), o.GroupBy); err != nil {
+					return
+				}
+				if i == 0 || current != group {
+					table.AddGroup(o.getHeaderLabel(o.GroupBy) + ": " + current)
+					group = current
+				}
+			}
+
+			var line []string
+			if line, err = o.getLine(items.Index(i)); err != nil {
+				return
+			}
+			table.AddRow(line...)
+		}
+
+		var footer []string
+		if footer, err = o.getFooter(items); err != nil {
+			return
+		} else if footer != nil {
+			table.AddFooter(footer...)
+		}
+
+		if o.Interactive {
+			err = o.outputInteractive(&table)
+		} else {
+			table.Render()
 		}
-		table.Render()
 	default:
 		err = fmt.Errorf("not support format %s", o.Format)
 	}
@@ -91,71 +281,335 @@ func (o *OutputOption) OutputV2(obj interface{}) (err error) {
 	return
 }
 
-// ListFilter filter the data list by fields
+// ListFilter filter the data list by fields, the invalid filters are ignored
+// Deprecated, see also ListFilterWithError
 func (o *OutputOption) ListFilter(obj interface{}) interface{} {
 	if len(o.Filter) == 0 {
 		return obj
 	}
 
+	filters := o.parseValidFilters()
 	elemType := reflect.TypeOf(obj).Elem()
 	elemSlice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, 10)
 	items := reflect.ValueOf(obj)
 	for i := 0; i < items.Len(); i++ {
 		item := items.Index(i)
-		if o.Match(item) {
+		if ok, err := matchFilters(filters, item); ok && err == nil {
 			elemSlice = reflect.Append(elemSlice, item)
 		}
 	}
 	return elemSlice.Interface()
 }
 
-// Match filter an item
+// ListFilterWithError filters the data list by fields, it returns an error if any filter is invalid
+func (o *OutputOption) ListFilterWithError(obj interface{}) (result interface{}, err error) {
+	return o.filterList(obj)
+}
+
+func (o *OutputOption) filterList(obj interface{}) (result interface{}, err error) {
+	if len(o.Filter) == 0 {
+		return obj, nil
+	}
+
+	var filters []*Filter
+	if filters, err = o.parseFilters(); err != nil {
+		return
+	}
+
+	elemType := reflect.TypeOf(obj).Elem()
+	elemSlice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, 10)
+	items := reflect.ValueOf(obj)
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+
+		var ok bool
+		if ok, err = matchFilters(filters, item); err != nil {
+			return
+		} else if ok {
+			elemSlice = reflect.Append(elemSlice, item)
+		}
+	}
+	result = elemSlice.Interface()
+	return
+}
+
+// Match filter an item, the invalid filters are ignored as same as ListFilter
+// Deprecated, see also MatchWithError
 func (o *OutputOption) Match(item reflect.Value) bool {
-	for _, f := range o.Filter {
-		arr := strings.Split(f, "=")
-		if len(arr) < 2 {
+	ok, err := matchFilters(o.parseValidFilters(), item)
+	return ok && err == nil
+}
+
+// MatchWithError returns true if the item matches all the filters, it returns an error if any filter is invalid
+func (o *OutputOption) MatchWithError(item reflect.Value) (ok bool, err error) {
+	var filters []*Filter
+	if filters, err = o.parseFilters(); err == nil {
+		ok, err = matchFilters(filters, item)
+	}
+	return
+}
+
+func (o *OutputOption) parseFilters() (filters []*Filter, err error) {
+	for _, text := range o.Filter {
+		var filter *Filter
+		if filter, err = ParseFilter(text); err != nil {
+			return
+		}
+		filters = append(filters, filter)
+	}
+	return
+}
+
+// matchFilters returns true if the item matches all the filters
+// parseValidFilters returns the filters which are valid, it's only for the deprecated functions
+func (o *OutputOption) parseValidFilters() (filters []*Filter) {
+	for _, text := range o.Filter {
+		if filter, err := ParseFilter(text); err == nil {
+			filters = append(filters, filter)
+		}
+	}
+	return
+}
+
+func matchFilters(filters []*Filter, item reflect.Value) (ok bool, err error) {
+	ok = true
+	for _, filter := range filters {
+		if ok, err = filter.Match(item); !ok || err != nil {
+			break
+		}
+	}
+	return
+}
+
+// GetHeaders returns the headers of the table, the header label is used if it exists
+func (o *OutputOption) GetHeaders() (headers []string) {
+	for _, col := range o.getColumns() {
+		headers = append(headers, o.getHeaderLabel(col.field))
+	}
+	return
+}
+
+// getHeaderLabel returns the label of the field, it's the field itself if there's no label
+func (o *OutputOption) getHeaderLabel(field string) string {
+	if label, ok := o.HeaderLabels[field]; ok && label != "" {
+		return label
+	}
+	return field
+}
+
+// getColumns parses the columns, a column could have a renderer like Size:bytes
+func (o *OutputOption) getColumns() (columns []column) {
+	for _, col := range strings.Split(o.Columns, ",") {
+		items := strings.SplitN(col, ":", 2)
+		item := column{field: items[0]}
+		if len(items) > 1 {
+			item.renderer = items[1]
+		}
+		columns = append(columns, item)
+	}
+	return
+}
+
+// getPresetColumns returns the columns if the output format is a preset
+func (o *OutputOption) getPresetColumns() (columns string, ok bool) {
+	if isOutputFormat(o.Format) {
+		// the built-in formats cannot be replaced by the presets
+		return
+	} else if columns, ok = o.ColumnPresets[o.Format]; ok {
+		return
+	}
+
+	if o.Format == WideOutputFormat {
+		columns, ok = o.Columns, true
+		if o.WideColumns != "" {
+			columns = columns + "," + o.WideColumns
+		}
+	}
+	return
+}
+
+// getPresetNames returns the names of all the column presets
+func (o *OutputOption) getPresetNames() (names []string) {
+	if o.WideColumns != "" {
+		names = append(names, WideOutputFormat)
+	}
+	for name := range o.ColumnPresets {
+		if isOutputFormat(name) {
 			continue
+		} else if name != WideOutputFormat || o.WideColumns == "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// LoadColumnPresets loads the column presets of a command from a YAML file, the content looks like:
+//
+//	jcli version list:
+//	  mine: Tag,Installed
+//
+// Nothing happens if the file does not exist
+func (o *OutputOption) LoadColumnPresets(cmd *cobra.Command, file string) (err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(file); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
 		}
+		return
+	}
 
-		key := arr[0]
-		val := arr[1]
+	presets := map[string]map[string]string{}
+	if err = yaml.Unmarshal(data, &presets); err != nil {
+		err = fmt.Errorf("cannot parse the column presets file %s, error: %v", file, err)
+		return
+	}
+
+	if o.ColumnPresets == nil {
+		o.ColumnPresets = map[string]string{}
+	}
+	for name, columns := range presets[cmd.CommandPath()] {
+		if isOutputFormat(name) {
+			err = fmt.Errorf("the column preset %s in %s conflicts with the output format", name, file)
+			return
+		}
+		o.ColumnPresets[name] = columns
+	}
+	return
+}
 
-		if !strings.Contains(ReflectFieldValueAsString(item, key), val) {
-			return false
+// isOutputFormat returns true if the name is a built-in output format, e.g. json, go-template
+func isOutputFormat(name string) bool {
+	for _, format := range OutputFormats {
+		if strings.TrimSuffix(format, "=") == name {
+			return true
 		}
 	}
-	return true
+	return false
 }
 
-// GetLine returns the line of a table
+// GetLine returns the line of a table, the cell is empty if its field is invalid
 func (o *OutputOption) GetLine(obj reflect.Value) []string {
-	columns := strings.Split(o.Columns, ",")
-	values := make([]string, 0)
+	values, _ := o.getLine(obj)
+	return values
+}
+
+// getLine returns the line of a table and the first error of the cells, the line always has all the columns
+func (o *OutputOption) getLine(obj reflect.Value) (values []string, err error) {
+	columns := o.getColumns()
+	values = make([]string, 0, len(columns))
 
 	if o.CellRenderMap == nil {
 		o.CellRenderMap = make(map[string]RenderCell, 0)
 	}
 
 	for _, col := range columns {
-		cell := ReflectFieldValueAsString(obj, col)
-		if renderCell, ok := o.CellRenderMap[col]; ok && renderCell != nil {
-			cell = renderCell(cell)
+		cell, cellErr := ReflectFieldValueAsStringWithError(obj, col.field)
+		if cellErr == nil {
+			cell, cellErr = o.renderCell(col, cell)
+		}
+		if cellErr != nil {
+			cell = ""
+			if err == nil {
+				err = cellErr
+			}
 		}
-
 		values = append(values, cell)
 	}
-	return values
+	return
+}
+
+// renderCell renders the cell of the column
+func (o *OutputOption) renderCell(col column, cell string) (result string, err error) {
+	result = cell
+	// the renderer in the column takes precedence over the one from the CellRenderMap
+	if col.renderer != "" {
+		var renderCell RenderCell
+		if renderCell, err = GetCellRenderer(col.renderer); err == nil {
+			result = renderCell(cell)
+		}
+	} else if renderCell, ok := o.CellRenderMap[col.field]; ok && renderCell != nil {
+		result = renderCell(cell)
+	}
+	result = colorFor(o.Color, o.Writer, result)
+	return
+}
+
+// checkFields makes sure the columns and filters are valid for the type of the items
+func (o *OutputOption) checkFields(obj interface{}, format string) (err error) {
+	objType := reflect.TypeOf(obj)
+	if objType == nil || (objType.Kind() != reflect.Slice && objType.Kind() != reflect.Array) {
+		return
+	}
+	elemType := objType.Elem()
+
+	switch format {
+	case TableOutputFormat, "", CSVOutputFormat, TSVOutputFormat, MarkdownOutputFormat:
+		for _, col := range o.getColumns() {
+			if err = checkFieldPath(elemType, col.field); err != nil {
+				err = fmt.Errorf("invalid column %s, error: %v", col.field, err)
+				return
+			}
+			if col.renderer != "" {
+				if _, err = GetCellRenderer(col.renderer); err != nil {
+					err = fmt.Errorf("invalid column %s, error: %v", col.field, err)
+					return
+				}
+			}
+		}
+	}
+
+	if format == TableOutputFormat || format == "" {
+		if err = o.checkAggregates(elemType); err != nil {
+			return
+		}
+	}
+
+	var keys []sortKey
+	if keys, err = o.parseSortKeys(); err != nil {
+		return
+	}
+	for _, key := range keys {
+		if err = checkFieldPath(elemType, key.field); err != nil {
+			err = fmt.Errorf("invalid sort key %s, error: %v", key.field, err)
+			return
+		}
+	}
+
+	var filters []*Filter
+	if filters, err = o.parseFilters(); err != nil {
+		return
+	}
+	for _, filter := range filters {
+		for _, field := range filter.Fields() {
+			if err = checkFieldPath(elemType, field); err != nil {
+				err = fmt.Errorf("invalid filter %s, error: %v", filter, err)
+				return
+			}
+		}
+	}
+	return
 }
 
 // SetFlag set flag of output format
 // Deprecated, see also SetFlagWithHeaders
 func (o *OutputOption) SetFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Format, "output", "o", TableOutputFormat,
-		"Format the output, supported formats: table, json, yaml")
+		"Format the output, supported formats: table, wide, json, yaml, csv, tsv, markdown, go-template=..., go-template-file=..., "+
+			"jsonpath=..., custom-columns=NAME:.path,...")
 	cmd.Flags().BoolVarP(&o.WithoutHeaders, "no-headers", "", false,
 		`When using the default output format, don't print headers (default print headers)`)
 	cmd.Flags().StringArrayVarP(&o.Filter, "filter", "", []string{},
-		"Filter for the list by fields")
+		"Filter for the list by fields, e.g. Name=foo (contains), Name==foo, Name=~^f, Age>=3, Phase in (a, b), "+
+			"!Name==foo, Name==foo || Age>3")
+
+	err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) (
+		i []string, directive cobra.ShellCompDirective) {
+		return append(OutputFormats, o.getPresetNames()...), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
+	if err != nil {
+		cmd.PrintErrf("register flag output for sub-command %s failed %#v\n", cmd.Name(), err)
+	}
 }
 
 // SetFlagWithHeaders set the flags of output
@@ -163,4 +617,43 @@ func (o *OutputOption) SetFlagWithHeaders(cmd *cobra.Command, headers string) {
 	o.SetFlag(cmd)
 	cmd.Flags().StringVarP(&o.Columns, "columns", "", headers,
 		"The columns of table")
+	cmd.Flags().StringSliceVarP(&o.SortBy, "sort-by", "", []string{},
+		"Sort the list by fields, add prefix '-' or suffix ':desc' for the descending order, e.g. Name,-Age")
+	cmd.Flags().IntVarP(&o.Limit, "limit", "", 0,
+		"The max count of the items, all the items will be output if it's not bigger than 0")
+	cmd.Flags().IntVarP(&o.Offset, "offset", "", 0,
+		"The count of the items to skip")
+	cmd.Flags().StringSliceVarP(&o.Aggregates, "aggregate", "", []string{},
+		"Add a footer with the aggregates of the columns, supported functions: "+strings.Join(AggregateFuncs, ", ")+
+			", e.g. Size:sum,Name:count")
+	cmd.Flags().StringVarP(&o.GroupBy, "group-by", "", "",
+		"Group the rows of the table by a field")
+}
+
+// SetColorFlag sets the flag of the color mode
+func (o *OutputOption) SetColorFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Color, "color", "", ColorAuto,
+		"Colorize the output, supported modes: "+strings.Join(ColorModes, ", "))
+
+	err := cmd.RegisterFlagCompletionFunc("color", func(cmd *cobra.Command, args []string, toComplete string) (
+		i []string, directive cobra.ShellCompDirective) {
+		return ColorModes, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		cmd.PrintErrf("register flag color for sub-command %s failed %#v\n", cmd.Name(), err)
+	}
+}
+
+// SetTableStyleFlag sets the flag of the border style of the table
+func (o *OutputOption) SetTableStyleFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.TableStyle, "table-style", "", "",
+		"The border style of the table, supported styles: "+strings.Join(GetBorderStyleNames(), ", "))
+
+	err := cmd.RegisterFlagCompletionFunc("table-style", func(cmd *cobra.Command, args []string, toComplete string) (
+		i []string, directive cobra.ShellCompDirective) {
+		return GetBorderStyleNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		cmd.PrintErrf("register flag table-style for sub-command %s failed %#v\n", cmd.Name(), err)
+	}
 }
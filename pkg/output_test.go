@@ -0,0 +1,254 @@
+package pkg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output test", func() {
+	type fakeItem struct {
+		Name      string
+		Owner     string
+		CreatedAt string
+	}
+
+	var (
+		buffer *bytes.Buffer
+		opt    *OutputOption
+		items  []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns:     "Name,CreatedAt",
+			WideColumns: "Owner",
+			Writer:      buffer,
+			HeaderLabels: map[string]string{
+				"CreatedAt": "AGE",
+			},
+		}
+		items = []fakeItem{{Name: "a", Owner: "bob", CreatedAt: "3m"}}
+	})
+
+	Context("column presets", func() {
+		It("header labels", func() {
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name AGE\na    3m\n"))
+		})
+
+		It("wide", func() {
+			opt.Format = WideOutputFormat
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name AGE Owner\na    3m  bob\n"))
+			Expect(opt.Format).To(Equal(WideOutputFormat))
+		})
+
+		It("custom preset", func() {
+			opt.ColumnPresets = map[string]string{"mine": "Owner,Name"}
+			opt.Format = "mine"
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Owner Name\nbob   a\n"))
+			Expect(opt.getPresetNames()).To(Equal([]string{"mine", "wide"}))
+		})
+
+		It("preset cannot replace a built-in format", func() {
+			opt.ColumnPresets = map[string]string{"json": "Owner"}
+			opt.Format = JSONOutputFormat
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(HavePrefix("["))
+			Expect(opt.getPresetNames()).To(Equal([]string{"wide"}))
+		})
+
+		It("load presets from file", func() {
+			dir, err := ioutil.TempDir("", "presets")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				_ = os.RemoveAll(dir)
+			}()
+
+			root := &cobra.Command{Use: "root"}
+			cmd := &cobra.Command{Use: "list"}
+			root.AddCommand(cmd)
+
+			file := filepath.Join(dir, "columns.yaml")
+			Expect(opt.LoadColumnPresets(cmd, file)).To(Succeed())
+			Expect(opt.ColumnPresets).To(BeEmpty())
+
+			Expect(ioutil.WriteFile(file, []byte(`root list:
+  mine: Owner
+root other:
+  other: Name
+`), 0644)).To(Succeed())
+			Expect(opt.LoadColumnPresets(cmd, file)).To(Succeed())
+			Expect(opt.ColumnPresets).To(Equal(map[string]string{"mine": "Owner"}))
+
+			Expect(ioutil.WriteFile(file, []byte("root list:\n  yaml: Owner\n"), 0644)).To(Succeed())
+			Expect(opt.LoadColumnPresets(cmd, file)).NotTo(Succeed())
+
+			Expect(ioutil.WriteFile(file, []byte(`invalid`), 0644)).To(Succeed())
+			Expect(opt.LoadColumnPresets(cmd, file)).NotTo(Succeed())
+		})
+	})
+
+	Context("single object", func() {
+		It("struct as key/value table", func() {
+			Expect(opt.OutputV2(items[0])).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name a\nAGE  3m\n"))
+		})
+
+		It("pointer of struct as json", func() {
+			opt.Format = JSONOutputFormat
+			Expect(opt.OutputV2(&items[0])).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring(`"Owner": "bob"`))
+		})
+
+		It("struct as csv", func() {
+			opt.Format = CSVOutputFormat
+			Expect(opt.OutputV2(items[0])).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name,AGE\na,3m\n"))
+		})
+
+		It("pointer of slice", func() {
+			Expect(opt.OutputV2(&items)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name AGE\na    3m\n"))
+		})
+
+		It("nil pointer of slice", func() {
+			var nilItems *[]fakeItem
+			Expect(opt.OutputV2(nilItems)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name AGE\n"))
+
+			buffer.Reset()
+			opt.Format = JSONOutputFormat
+			Expect(opt.OutputV2(nilItems)).To(Succeed())
+			Expect(buffer.String()).To(Equal("[]"))
+
+			Expect(opt.OutputV2((*fakeItem)(nil))).NotTo(Succeed())
+		})
+
+		It("map of structs", func() {
+			opt.Filter = []string{"Owner=bo"}
+			Expect(opt.OutputV2(map[string]fakeItem{
+				"b": {Name: "b", Owner: "bob"},
+				"a": {Name: "a", Owner: "bob"},
+				"c": {Name: "c", Owner: "alice"},
+			})).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name AGE\na    \nb    \n"))
+		})
+
+		It("map of scalars", func() {
+			Expect(opt.OutputV2(map[string]int{"b": 2, "a": 1})).To(Succeed())
+			Expect(buffer.String()).To(Equal("a 1\nb 2\n"))
+		})
+
+		It("unsupported kinds", func() {
+			Expect(opt.OutputV2("text")).NotTo(Succeed())
+			Expect(opt.OutputV2(nil)).NotTo(Succeed())
+		})
+	})
+
+	Context("aggregates and groups", func() {
+		type fakeFile struct {
+			Name    string
+			Owner   string
+			Size    int64
+			Elapsed time.Duration
+		}
+
+		var files []fakeFile
+
+		BeforeEach(func() {
+			opt.Columns = "Name,Owner,Size:bytes,Elapsed"
+			opt.HeaderLabels = nil
+			files = []fakeFile{
+				{Name: "a", Owner: "bob", Size: 1024, Elapsed: time.Second},
+				{Name: "b", Owner: "alice", Size: 2048, Elapsed: time.Minute},
+				{Name: "c", Owner: "bob", Size: 512, Elapsed: time.Second},
+			}
+		})
+
+		It("footer", func() {
+			opt.Aggregates = []string{"Size", "Elapsed:max"}
+			Expect(opt.OutputV2(files)).To(Succeed())
+			Expect(buffer.String()).To(Equal(`Name  Owner Size   Elapsed
+a     bob   1KiB   1s
+b     alice 2KiB   1m0s
+c     bob   512B   1s
+TOTAL       3.5KiB 1m0s
+`))
+		})
+
+		It("average without the nil values", func() {
+			size := 30
+			items := reflect.ValueOf([]struct{ Size *int }{{Size: &size}, {}, {Size: &size}})
+			result, err := aggregate(items, "Size", AggregateAvg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("30"))
+		})
+
+		It("group by", func() {
+			opt.Aggregates = []string{"Name:count", "Size:avg"}
+			opt.GroupBy = "Owner"
+			opt.TableStyle = "ascii"
+			Expect(opt.OutputV2(files)).To(Succeed())
+			Expect(buffer.String()).To(Equal(`+------+-------+--------+---------+
+| Name | Owner | Size   | Elapsed |
++------+-------+--------+---------+
+| Owner: bob                      |
++------+-------+--------+---------+
+| a    | bob   | 1KiB   | 1s      |
+| c    | bob   | 512B   | 1s      |
++------+-------+--------+---------+
+| Owner: alice                    |
++------+-------+--------+---------+
+| b    | alice | 2KiB   | 1m0s    |
++------+-------+--------+---------+
+| 3    |       | 1.2KiB |         |
++------+-------+--------+---------+
+`))
+		})
+
+		It("group by only in the table", func() {
+			opt.GroupBy = "Owner"
+			opt.Columns = "Name,Owner"
+			opt.Format = CSVOutputFormat
+			Expect(opt.OutputV2(files)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name,Owner\na,bob\nb,alice\nc,bob\n"))
+		})
+
+		It("invalid", func() {
+			opt.Columns = "Name,Owner.Fake"
+			opt.Aggregates = []string{"Owner.Fake:count"}
+			Expect(opt.checkAggregates(reflect.TypeOf(files[0]))).NotTo(Succeed())
+
+			opt.Columns = "Name,Owner,Size:bytes,Elapsed"
+			opt.Aggregates = []string{"Name:sum"}
+			Expect(opt.OutputV2(files)).NotTo(Succeed())
+			opt.Aggregates = []string{"Size:fake"}
+			Expect(opt.OutputV2(files)).NotTo(Succeed())
+			opt.Aggregates = []string{"Fake"}
+			Expect(opt.OutputV2(files)).NotTo(Succeed())
+			opt.Aggregates = nil
+			opt.GroupBy = "Fake"
+			Expect(opt.OutputV2(files)).NotTo(Succeed())
+		})
+	})
+	It("get the line with an invalid column", func() {
+		opt.Columns = "Name,Fake,CreatedAt"
+		Expect(opt.GetLine(reflect.ValueOf(items[0]))).To(Equal([]string{"a", "", "3m"}))
+
+		line, err := opt.getLine(reflect.ValueOf(items[0]))
+		Expect(err).To(HaveOccurred())
+		Expect(line).To(HaveLen(3))
+	})
+})
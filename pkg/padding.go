@@ -3,8 +3,6 @@ package pkg
 import (
 	"math"
 	"strings"
-	"unicode"
-	"unicode/utf8"
 )
 
 const (
@@ -57,71 +55,11 @@ func PadCenter(s, pad string, width int) string {
 	return s
 }
 
-func isHan(s string) (isHan bool) {
-	wh := []rune(s)
-	for _, r := range wh {
-		if unicode.Is(unicode.Han, r) {
-			isHan = true
-		} else if unicode.Is(unicode.Hiragana, r) {
-			isHan = true
-		} else if unicode.Is(unicode.Katakana, r) {
-			isHan = true
-		} else if unicode.Is(unicode.Common, r) {
-			isHan = true
-		} else {
-			isHan = false
-			break
-		}
-	}
-	return
-}
-
-func countCN(s string) (count int) {
-	wh := []rune(s)
-	for _, r := range wh {
-		if unicode.Is(unicode.Han, r) {
-			count++
-		} else if unicode.Is(unicode.Hiragana, r) {
-			count++
-		} else if unicode.Is(unicode.Katakana, r) {
-			count++
-		} else if unicode.Is(unicode.Common, r) && len(string(r)) != 1 {
-			count++
-		}
-	}
-	return
-}
-
 func widthValue(s string, width int) (gap int) {
-	l := utf8.RuneCountInString(s)
-	ln := len(s)
-	isHan := isHan(s)
-	count := countCN(s)
-	if ln != l {
-		if isHan {
-			gap = width - (ln - l)
-		} else {
-			gap = width - (ln - count)
-		}
-	} else {
-		gap = width - l
-	}
-	return
+	return width - displayWidth(s)
 }
 
-// Lenf counts the number
+// Lenf returns the display width of the text, e.g. a CJK character or an emoji takes two cells
 func Lenf(han string) (l int) {
-	ln := len(han)
-	l = utf8.RuneCountInString(han)
-	isHan := isHan(han)
-	count := countCN(han)
-	if ln != l {
-		if isHan {
-			l = ln - l
-		} else {
-			l = ln - count
-		}
-
-	}
-	return
+	return displayWidth(han)
 }
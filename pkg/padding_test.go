@@ -1,93 +1,55 @@
 package pkg
 
 import (
-	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Padding util test", func() {
-	var (
-		ctrl *gomock.Controller
+	DescribeTable("Lenf test", func(text string, expected int) {
+		Expect(Lenf(text)).To(Equal(expected))
+	},
+		Entry("english", "ZSOMZAYNHG", 10),
+		Entry("empty", "", 0),
+		Entry("chinese", "构建一个自由风格的软件项目", 26),
+		Entry("hk", "建置 Free-Style 軟體專案", 24),
+		Entry("japanese", "フリースタイル・プロジェクトのビルド", 36),
+		Entry("japanese and chinese and english", "フリースタイル・プ中ロジasdェクトのビルド", 41),
+		Entry("halfwidth katakana", "ｶﾀｶﾅ", 4),
+		Entry("korean", "한국어", 6),
+		Entry("korean with conjoining jamo", "\u1112\u1161\u11ab", 2),
+		Entry("korean and english", "한국어 test", 11),
+		Entry("fullwidth punctuation", "，。！", 6),
+		Entry("fullwidth latin", "ＡＢＣ", 6),
+		Entry("combining marks", "e\u0301te\u0301", 3),
+		Entry("emoji", "\U0001F600", 2),
+		Entry("emoji with modifier", "\U0001F44D\U0001F3FD", 2),
+		Entry("emoji with zwj", "\U0001F468\u200d\U0001F469\u200d\U0001F467", 2),
+		Entry("emoji presentation", "❤\ufe0f", 2),
+		Entry("text presentation", "❤", 1),
+		Entry("flag", "\U0001F1E8\U0001F1F3", 2),
+		Entry("zero width space", "a\u200bb", 2),
+		Entry("ansi", "\x1b[31m中文\x1b[0m", 4),
 	)
 
-	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-	})
-
-	AfterEach(func() {
-		ctrl.Finish()
-	})
-
-	Context("isHan test", func() {
-		var han string
-		It("english test", func() {
-			han = "ZSOMZAYNHG"
-			Expect(isHan(han)).To(Equal(false))
-		})
-		It("chinese test", func() {
-			han = "构建一个自由风格的软件项目"
-			Expect(isHan(han)).To(Equal(true))
-		})
-		It("hk test", func() {
-			han = "建置 Free-Style 軟體專案"
-			Expect(isHan(han)).To(Equal(false))
-		})
-		It("japanese test", func() {
-			han = "フリースタイル・プロジェクトのビルド"
-			Expect(isHan(han)).To(Equal(true))
-		})
-		It("japanese and chinese and english test", func() {
-			han = "フリースタイル・プ中ロジasdェクトのビルド"
-			Expect(isHan(han)).To(Equal(false))
-		})
-	})
-
-	Context("countCN test", func() {
-		var han string
-		It("english test", func() {
-			han = "ZSOMZAYNHG"
-			Expect(countCN(han)).To(Equal(0))
-		})
-		It("chinese test", func() {
-			han = "构建一个自由风格的软件项目"
-			Expect(countCN(han)).To(Equal(13))
-		})
-		It("hk test", func() {
-			han = "建置 Free-Style 軟體專案"
-			Expect(countCN(han)).To(Equal(6))
-		})
-		It("japanese test", func() {
-			han = "フリースタイル・プロジェクトのビルド"
-			Expect(countCN(han)).To(Equal(18))
-		})
-		It("japanese and chinese and english test", func() {
-			han = "フリースタイル・プ中ロジasdェクトのビルド"
-			Expect(countCN(han)).To(Equal(19))
-		})
-	})
+	DescribeTable("Pad test", func(text string, align int, expected string) {
+		Expect(Pad(text, " ", 6, align)).To(Equal(expected))
+	},
+		Entry("english left", "ab", AlignLeft, "ab    "),
+		Entry("chinese right", "中文", AlignRight, "  中文"),
+		Entry("korean center", "한", AlignCenter, "  한  "),
+		Entry("emoji", "\U0001F44D\U0001F3FD", AlignLeft, "\U0001F44D\U0001F3FD    "),
+		Entry("combining marks", "e\u0301", AlignLeft, "e\u0301     "),
+		Entry("too long", "中文中文", AlignLeft, "中文中文"),
+	)
 
-	Context("Lenf test", func() {
-		var han string
-		It("english test", func() {
-			han = "ZSOMZAYNHG"
-			Expect(Lenf(han) == len(han)).To(Equal(true))
-		})
-		It("chinese test", func() {
-			han = "构建一个自由风格的软件项目"
-			Expect(Lenf(han) == len(han)).To(Equal(false))
-		})
-		It("hk test", func() {
-			han = "建置 Free-Style 軟體專案"
-			Expect(Lenf(han) == len(han)).To(Equal(false))
-		})
-		It("japanese test", func() {
-			han = "フリースタイル・プロジェクトのビルド"
-			Expect(Lenf(han) == len(han)).To(Equal(false))
-		})
-		It("japanese and chinese and english test", func() {
-			han = "フリースタイル・プ中ロジasdェクトのビルド"
-			Expect(Lenf(han) == len(han)).To(Equal(false))
-		})
-	})
+	DescribeTable("Truncate test", func(text string, width int, expected string) {
+		Expect(Truncate(text, width)).To(Equal(expected))
+		Expect(Lenf(Truncate(text, width))).To(BeNumerically("<=", width))
+	},
+		Entry("chinese", "构建一个自由", 5, "构建…"),
+		Entry("emoji with zwj", "\U0001F468\u200d\U0001F469\u200d\U0001F467abc", 4, "\U0001F468\u200d\U0001F469\u200d\U0001F467a…"),
+		Entry("combining marks", "e\u0301e\u0301e\u0301", 2, "e\u0301…"),
+	)
 })
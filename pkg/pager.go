@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultPager is the pager command if the environment variable PAGER is empty
+const DefaultPager = "less -FRX"
+
+const (
+	// pagerEnv is the environment variable of the pager command
+	pagerEnv = "PAGER"
+	// noPagerEnv disables the pager if it's not empty
+	noPagerEnv = "NO_PAGER"
+)
+
+// GetPagerCommand returns the pager command, it comes from the environment variable PAGER
+func GetPagerCommand() string {
+	if pager := strings.TrimSpace(os.Getenv(pagerEnv)); pager != "" {
+		return pager
+	}
+	return DefaultPager
+}
+
+// SetPagerFlag enables the pager, and sets the flag to disable it
+func (o *OutputOption) SetPagerFlag(cmd *cobra.Command) {
+	o.Pager = true
+	cmd.Flags().BoolVarP(&o.NoPager, "no-pager", "", false,
+		"Do not pipe the output through the pager, the pager comes from the environment variable PAGER, "+
+			"the default one is '"+DefaultPager+"'")
+}
+
+// usePager returns the terminal if the output should be piped through the pager
+func (o *OutputOption) usePager() (file *os.File, ok bool) {
+	if !o.Pager || o.NoPager || o.Interactive || os.Getenv(noPagerEnv) != "" {
+		return
+	}
+
+	if file, ok = o.Writer.(*os.File); ok {
+		ok = isTerminal(file)
+	}
+	return
+}
+
+// outputWithPager outputs into a buffer, and pipes it through the pager if it's taller than the terminal
+func (o *OutputOption) outputWithPager(file *os.File, output func(*OutputOption) error) (err error) {
+	// the buffer has the same width and color as the terminal
+	buffer := &terminalBuffer{terminal: file}
+	option := *o
+	option.Writer = buffer
+	option.NoPager = true
+	if err = output(&option); err != nil {
+		return
+	}
+
+	command := strings.Fields(GetPagerCommand())
+	if height := TerminalHeight(file); height > 0 && bytes.Count(buffer.Bytes(), []byte("\n")) >= height {
+		if _, lookErr := exec.LookPath(command[0]); lookErr == nil {
+			return runPager(command, buffer, file)
+		}
+	}
+	// print it directly if it's short or the pager is not available
+	_, err = file.Write(buffer.Bytes())
+	return
+}
+
+// runPager runs the pager command with the input, the first item of the command is the program
+func runPager(command []string, in io.Reader, out io.Writer) (err error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
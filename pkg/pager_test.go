@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pager test", func() {
+	var pager string
+
+	BeforeEach(func() {
+		pager = os.Getenv(pagerEnv)
+	})
+
+	AfterEach(func() {
+		Expect(os.Setenv(pagerEnv, pager)).To(Succeed())
+	})
+
+	It("pager command", func() {
+		Expect(os.Setenv(pagerEnv, "")).To(Succeed())
+		Expect(GetPagerCommand()).To(Equal(DefaultPager))
+
+		Expect(os.Setenv(pagerEnv, "more")).To(Succeed())
+		Expect(GetPagerCommand()).To(Equal("more"))
+	})
+
+	It("use pager", func() {
+		opt := &OutputOption{Writer: os.Stdout}
+		_, ok := opt.usePager()
+		Expect(ok).To(BeFalse())
+
+		opt.Pager = true
+		opt.NoPager = true
+		_, ok = opt.usePager()
+		Expect(ok).To(BeFalse())
+
+		opt.NoPager = false
+		opt.Writer = new(bytes.Buffer)
+		_, ok = opt.usePager()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("run pager", func() {
+		buffer := new(bytes.Buffer)
+		err := runPager([]string{"cat"}, strings.NewReader("fake\n"), buffer)
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal("fake\n"))
+	})
+
+	It("output without a terminal", func() {
+		file, err := ioutil.TempFile("", "pager")
+		Expect(err).To(BeNil())
+		defer func() {
+			_ = os.Remove(file.Name())
+		}()
+
+		opt := &OutputOption{Columns: "Name", Writer: file, Pager: true}
+		err = opt.outputWithPager(file, func(option *OutputOption) error {
+			Expect(option.NoPager).To(BeTrue())
+			// the buffered output has the same width and color as the terminal
+			terminal, ok := terminalFile(option.Writer)
+			Expect(ok).To(BeTrue())
+			Expect(terminal).To(Equal(file))
+			return option.OutputV2([]struct{ Name string }{{Name: "fake"}})
+		})
+		Expect(err).To(BeNil())
+		Expect(file.Close()).To(Succeed())
+
+		data, err := ioutil.ReadFile(file.Name())
+		Expect(err).To(BeNil())
+		Expect(string(data)).To(Equal("Name\nfake\n"))
+	})
+})
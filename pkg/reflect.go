@@ -3,9 +3,204 @@ package pkg
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
-// ReflectFieldValueAsString returns the value of a field
+// ReflectFieldValueAsString returns the value of a field, it's empty if the field is not found
 func ReflectFieldValueAsString(v reflect.Value, field string) string {
-	return fmt.Sprint(reflect.Indirect(v).FieldByName(field))
+	value, _ := ReflectFieldValueAsStringWithError(v, field)
+	return value
+}
+
+// ReflectFieldValueAsStringWithError returns the value of a field path as string
+func ReflectFieldValueAsStringWithError(v reflect.Value, path string) (value string, err error) {
+	var result reflect.Value
+	if result, err = ReflectFieldValue(v, path); err == nil && result.IsValid() {
+		value = fmt.Sprint(result)
+	}
+	return
+}
+
+// ReflectFieldValue returns the value of a field path, the path could be like:
+// Spec.Owner.Name, Labels.app, Labels[app.kubernetes.io/name], Items[0].Name.
+// The JSON tag name is an alias of a struct field.
+// The returned value is invalid if a map key or slice index does not exist.
+func ReflectFieldValue(v reflect.Value, path string) (result reflect.Value, err error) {
+	var segments []string
+	if segments, err = splitFieldPath(path); err != nil {
+		return
+	}
+
+	result = v
+	for i, segment := range segments {
+		result = indirectValue(result)
+		if !result.IsValid() {
+			// nil pointer or interface
+			return
+		}
+
+		switch result.Kind() {
+		case reflect.Struct:
+			field, ok := findStructField(result.Type(), segment)
+			if !ok {
+				err = fmt.Errorf("field %s is not found in %s", strings.Join(segments[:i+1], "."), result.Type())
+				return
+			}
+			// walk the embedded structs step by step, the promoted field is empty if an embedded pointer is nil
+			for j, index := range field.Index {
+				if j > 0 {
+					if result = indirectValue(result); !result.IsValid() {
+						return
+					}
+				}
+				result = result.Field(index)
+			}
+		case reflect.Map:
+			var key reflect.Value
+			if key, err = convertMapKey(segment, result.Type().Key()); err != nil {
+				err = fmt.Errorf("invalid key %s of %s, error: %v", segment, result.Type(), err)
+				return
+			}
+			result = result.MapIndex(key)
+		case reflect.Slice, reflect.Array:
+			var index int
+			if index, err = strconv.Atoi(segment); err != nil {
+				err = fmt.Errorf("invalid index %s of %s", segment, result.Type())
+				return
+			}
+			if index < 0 || index >= result.Len() {
+				result = reflect.Value{}
+				return
+			}
+			result = result.Index(index)
+		default:
+			err = fmt.Errorf("cannot get field %s from %s", strings.Join(segments[:i+1], "."), result.Type())
+			return
+		}
+	}
+	return
+}
+
+// checkFieldPath checks if a field path is valid for a type, only the struct fields could be checked
+func checkFieldPath(t reflect.Type, path string) (err error) {
+	_, err = resolveFieldType(t, path)
+	return
+}
+
+// resolveFieldType returns the type of the field path, it's nil if the type is unknown until having the value
+func resolveFieldType(t reflect.Type, path string) (result reflect.Type, err error) {
+	var segments []string
+	if segments, err = splitFieldPath(path); err != nil {
+		return
+	}
+
+	for i, segment := range segments {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := findStructField(t, segment)
+			if !ok {
+				err = fmt.Errorf("field %s is not found in %s", strings.Join(segments[:i+1], "."), t)
+				return
+			}
+			t = field.Type
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Interface:
+			// cannot know the type until having the value
+			return
+		default:
+			err = fmt.Errorf("cannot get field %s from %s", strings.Join(segments[:i+1], "."), t)
+			return
+		}
+	}
+	result = t
+	return
+}
+
+// splitFieldPath splits a path like Items[0].Labels[app.kubernetes.io/name] to segments
+func splitFieldPath(path string) (segments []string, err error) {
+	rest := path
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				err = fmt.Errorf("unclosed bracket in field path %s", path)
+				return
+			}
+			segments = append(segments, strings.Trim(rest[1:end], `'"`))
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		err = fmt.Errorf("field path is empty")
+	}
+	return
+}
+
+func findStructField(t reflect.Type, name string) (field reflect.StructField, ok bool) {
+	if field, ok = t.FieldByName(name); ok {
+		return
+	}
+
+	// try to find it by the JSON tag name
+	for i := 0; i < t.NumField(); i++ {
+		item := t.Field(i)
+		if tagName := strings.Split(item.Tag.Get("json"), ",")[0]; tagName != "" && tagName == name {
+			return item, true
+		}
+	}
+	return
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func convertMapKey(key string, keyType reflect.Type) (value reflect.Value, err error) {
+	switch keyType.Kind() {
+	case reflect.String:
+		value = reflect.ValueOf(key).Convert(keyType)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var number int64
+		if number, err = strconv.ParseInt(key, 10, 64); err == nil {
+			value = reflect.ValueOf(number).Convert(keyType)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var number uint64
+		if number, err = strconv.ParseUint(key, 10, 64); err == nil {
+			value = reflect.ValueOf(number).Convert(keyType)
+		}
+	case reflect.Bool:
+		var flag bool
+		if flag, err = strconv.ParseBool(key); err == nil {
+			value = reflect.ValueOf(flag).Convert(keyType)
+		}
+	case reflect.Interface:
+		value = reflect.ValueOf(key)
+	default:
+		err = fmt.Errorf("not support key type %s", keyType)
+	}
+	return
 }
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reflect util test", func() {
+	type fakeOwner struct {
+		Name string `json:"name"`
+	}
+	type fakeSpec struct {
+		Owner  *fakeOwner
+		Labels map[string]string
+		Ports  []int `json:"ports,omitempty"`
+	}
+	type fakeMeta struct {
+		Namespace string
+	}
+	type fakeItem struct {
+		*fakeMeta
+		Name string
+		Spec fakeSpec `json:"spec"`
+		Any  interface{}
+	}
+
+	item := fakeItem{
+		Name: "fake",
+		Spec: fakeSpec{
+			Owner:  &fakeOwner{Name: "bob"},
+			Labels: map[string]string{"app": "demo", "app.kubernetes.io/name": "fake"},
+			Ports:  []int{80, 443},
+		},
+		Any: map[string]interface{}{"key": "value"},
+	}
+
+	DescribeTable("field path",
+		func(path, expected string) {
+			value, err := ReflectFieldValueAsStringWithError(reflect.ValueOf(item), path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(expected))
+		},
+		Entry("top level", "Name", "fake"),
+		Entry("nested struct with pointer", "Spec.Owner.Name", "bob"),
+		Entry("json tag name", "spec.Owner.name", "bob"),
+		Entry("map key", "Spec.Labels.app", "demo"),
+		Entry("map key with brackets", "Spec.Labels[app.kubernetes.io/name]", "fake"),
+		Entry("missing map key", "Spec.Labels.missing", ""),
+		Entry("slice index", "Spec.ports[1]", "443"),
+		Entry("slice index with dot", "Spec.Ports.0", "80"),
+		Entry("out of range", "Spec.Ports[5]", ""),
+		Entry("interface", "Any.key", "value"),
+		Entry("nil embedded pointer", "Namespace", ""),
+	)
+
+	DescribeTable("invalid field path",
+		func(obj interface{}, path string) {
+			_, err := ReflectFieldValueAsStringWithError(reflect.ValueOf(obj), path)
+			Expect(err).To(HaveOccurred())
+			Expect(ReflectFieldValueAsString(reflect.ValueOf(obj), path)).To(BeEmpty())
+		},
+		Entry("unknown field", item, "Fake"),
+		Entry("unknown nested field", item, "Spec.Owner.Fake"),
+		Entry("invalid index", item, "Spec.Ports.first"),
+		Entry("not a struct", "fake", "Name"),
+		Entry("empty path", item, ""),
+	)
+
+	It("check the field path by type", func() {
+		itemType := reflect.TypeOf(item)
+		Expect(checkFieldPath(itemType, "Spec.Owner.Name")).To(Succeed())
+		Expect(checkFieldPath(itemType, "Spec.Labels.anything")).To(Succeed())
+		Expect(checkFieldPath(itemType, "Any.anything")).To(Succeed())
+		Expect(checkFieldPath(itemType, "Spec.Owner.Fake")).NotTo(Succeed())
+		Expect(checkFieldPath(itemType, "Name.Fake")).NotTo(Succeed())
+	})
+
+	It("output with unknown column", func() {
+		opt := &OutputOption{
+			Columns: "Name,Fake",
+			Writer:  new(bytes.Buffer),
+		}
+		err := opt.OutputV2([]fakeItem{item})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Fake"))
+	})
+})
@@ -0,0 +1,234 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CellRendererFactory creates a cell renderer, the argument comes from the column like Description:truncate=20
+type CellRendererFactory func(arg string) (RenderCell, error)
+
+// CellRenderers are the renderers which could be used by name in the columns, e.g. Name,Size:bytes,Created:age
+var CellRenderers = map[string]CellRendererFactory{
+	"age":      noArgRenderer(RenderAge),
+	"bytes":    noArgRenderer(RenderBytes),
+	"duration": noArgRenderer(RenderDuration),
+	"bool":     noArgRenderer(RenderBool),
+	"status":   noArgRenderer(RenderStatus),
+	"truncate": newTruncateRenderer,
+}
+
+// defaultTruncateWidth is the width of the truncate renderer if there's no argument
+const defaultTruncateWidth = 20
+
+// the statuses which are rendered in different colors
+var (
+	successStatuses = []string{"running", "success", "succeeded", "ready", "active", "healthy",
+		"ok", "completed", "passed", "true", "enabled", "online", "up"}
+	failureStatuses = []string{"failed", "failure", "error", "crashloopbackoff", "unhealthy",
+		"false", "disabled", "offline", "down", "aborted"}
+	warningStatuses = []string{"pending", "waiting", "unknown", "creating", "terminating",
+		"warning", "unstable", "building", "queued"}
+)
+
+// RegisterCellRenderer registers a renderer which could be used by name in the columns
+func RegisterCellRenderer(name string, factory CellRendererFactory) {
+	CellRenderers[name] = factory
+}
+
+// GetCellRenderer returns a renderer by its spec, e.g. age, truncate=20
+func GetCellRenderer(spec string) (render RenderCell, err error) {
+	name, arg := spec, ""
+	if index := strings.Index(spec, "="); index >= 0 {
+		name, arg = spec[:index], spec[index+1:]
+	}
+
+	factory, ok := CellRenderers[name]
+	if !ok || factory == nil {
+		err = fmt.Errorf("unknown cell renderer %s", name)
+		return
+	}
+	render, err = factory(arg)
+	return
+}
+
+// RenderAge renders a time as the relative time, e.g. 3m ago
+func RenderAge(cell string) string {
+	t, err := parseCellTime(cell)
+	if err != nil {
+		return cell
+	} else if t.IsZero() {
+		return ""
+	}
+
+	duration := time.Since(t)
+	if duration < 0 {
+		return "in " + humanizeDuration(-duration)
+	}
+	return humanizeDuration(duration) + " ago"
+}
+
+// RenderBytes renders a number as the byte size, e.g. 1.5KiB
+func RenderBytes(cell string) string {
+	size, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return cell
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := 0
+	for ; math.Abs(size) >= 1024 && i < len(units)-1; i++ {
+		size /= 1024
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d%s", int64(size), units[i])
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(fmt.Sprintf("%.1f", size), "0"), ".") + units[i]
+}
+
+// RenderDuration renders a duration in a short way, e.g. 1h2m. The number is treated as seconds
+func RenderDuration(cell string) string {
+	duration, err := time.ParseDuration(cell)
+	if err != nil {
+		seconds, numErr := strconv.ParseFloat(cell, 64)
+		if numErr != nil {
+			return cell
+		}
+		duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	if duration < 0 {
+		return "-" + humanizeDuration(-duration)
+	}
+	return humanizeDuration(duration)
+}
+
+// RenderBool renders a bool as ✓ or ✗
+func RenderBool(cell string) string {
+	value, err := strconv.ParseBool(cell)
+	switch {
+	case err != nil:
+		return cell
+	case value:
+		return "✓"
+	default:
+		return "✗"
+	}
+}
+
+// RenderStatus renders a status in different colors, e.g. Running is green, Failed is red
+func RenderStatus(cell string) string {
+	status := strings.ToLower(strings.TrimSpace(cell))
+	switch {
+	case containsString(successStatuses, status):
+		return colorize(cell, ColorGreen)
+	case containsString(failureStatuses, status):
+		return colorize(cell, ColorRed)
+	case containsString(warningStatuses, status):
+		return colorize(cell, ColorYellow)
+	}
+	return cell
+}
+
+// Truncate cuts the text to the width with an ellipsis
+func Truncate(text string, width int) string {
+	if width <= 0 || Lenf(text) <= width {
+		return text
+	}
+	return cutWidth(text, width-1) + ellipsis
+}
+
+func newTruncateRenderer(arg string) (render RenderCell, err error) {
+	width := defaultTruncateWidth
+	if arg != "" {
+		if width, err = strconv.Atoi(arg); err != nil || width <= 0 {
+			err = fmt.Errorf("invalid width '%s' of the truncate renderer", arg)
+			return
+		}
+	}
+
+	render = func(cell string) string {
+		return Truncate(cell, width)
+	}
+	return
+}
+
+func noArgRenderer(render RenderCell) CellRendererFactory {
+	return func(arg string) (RenderCell, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("the renderer does not accept any argument")
+		}
+		return render, nil
+	}
+}
+
+// cellTimeLayouts are the layouts of the time as a cell, the first one is the format of fmt.Sprint
+var cellTimeLayouts = []string{"2006-01-02 15:04:05.999999999 -0700 MST", time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+func parseCellTime(cell string) (t time.Time, err error) {
+	// remove the monotonic clock reading, e.g. m=+0.001
+	if index := strings.Index(cell, " m="); index >= 0 {
+		cell = cell[:index]
+	}
+
+	for _, layout := range cellTimeLayouts {
+		if t, err = time.Parse(layout, cell); err == nil {
+			return
+		}
+	}
+	return
+}
+
+// humanizeDuration returns at most two units of a duration, e.g. 3m, 2h5m, 3d4h, 2y30d
+func humanizeDuration(d time.Duration) string {
+	const (
+		day  = 24 * time.Hour
+		year = 365 * day
+	)
+
+	switch {
+	case d < time.Second:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < 10*time.Minute:
+		return trimZeroUnit(fmt.Sprintf("%dm%ds", int(d/time.Minute), int(d%time.Minute/time.Second)))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 10*time.Hour:
+		return trimZeroUnit(fmt.Sprintf("%dh%dm", int(d/time.Hour), int(d%time.Hour/time.Minute)))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 10*day:
+		return trimZeroUnit(fmt.Sprintf("%dd%dh", int(d/day), int(d%day/time.Hour)))
+	case d < year:
+		return fmt.Sprintf("%dd", int(d/day))
+	default:
+		return trimZeroUnit(fmt.Sprintf("%dy%dd", int(d/year), int(d%year/day)))
+	}
+}
+
+// trimZeroUnit removes the last unit if it's zero, e.g. 3m0s -> 3m
+func trimZeroUnit(text string) string {
+	if strings.HasSuffix(text, "0s") || strings.HasSuffix(text, "0m") ||
+		strings.HasSuffix(text, "0h") || strings.HasSuffix(text, "0d") {
+		trimmed := text[:len(text)-2]
+		// make sure it's not a number like 10s
+		if last := trimmed[len(trimmed)-1]; last < '0' || last > '9' {
+			return trimmed
+		}
+	}
+	return text
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
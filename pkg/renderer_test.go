@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cell renderer test", func() {
+	DescribeTable("renderers",
+		func(spec, cell, expected string) {
+			render, err := GetCellRenderer(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(render(cell)).To(Equal(expected))
+		},
+		Entry("age", "age", fmt.Sprint(time.Now().Add(-30*time.Minute)), "30m ago"),
+		Entry("age in the future", "age", time.Now().Add(50*time.Hour+30*time.Minute).Format(time.RFC3339), "in 2d2h"),
+		Entry("age of zero time", "age", fmt.Sprint(time.Time{}), ""),
+		Entry("age of invalid time", "age", "fake", "fake"),
+		Entry("bytes", "bytes", "1536", "1.5KiB"),
+		Entry("small bytes", "bytes", "12", "12B"),
+		Entry("large bytes", "bytes", "1073741824", "1GiB"),
+		Entry("duration", "duration", "1h2m3s", "1h2m"),
+		Entry("duration in seconds", "duration", "90", "1m30s"),
+		Entry("long duration", "duration", "800h", "33d"),
+		Entry("bool true", "bool", "true", "✓"),
+		Entry("bool false", "bool", "false", "✗"),
+		Entry("not bool", "bool", "fake", "fake"),
+		Entry("truncate", "truncate=5", "hello world", "hell…"),
+		Entry("short text", "truncate", "hello", "hello"),
+	)
+
+	It("invalid renderers", func() {
+		_, err := GetCellRenderer("fake")
+		Expect(err).To(HaveOccurred())
+		_, err = GetCellRenderer("truncate=abc")
+		Expect(err).To(HaveOccurred())
+		_, err = GetCellRenderer("age=1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("status", func() {
+		Expect(os.Setenv("NO_COLOR", "")).To(Succeed())
+		Expect(RenderStatus("Running")).To(Equal("Running"))
+		Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+
+		Expect(SetColorMode(ColorAlways)).To(Succeed())
+		defer func() {
+			_ = SetColorMode(ColorAuto)
+		}()
+		Expect(RenderStatus("Running")).To(Equal("\x1b[32mRunning\x1b[0m"))
+		Expect(RenderStatus("Failed")).To(Equal("\x1b[31mFailed\x1b[0m"))
+		Expect(RenderStatus("Fake")).To(Equal("Fake"))
+	})
+
+	It("register a renderer", func() {
+		RegisterCellRenderer("fake", func(arg string) (RenderCell, error) {
+			return func(cell string) string {
+				return arg + cell
+			}, nil
+		})
+		defer delete(CellRenderers, "fake")
+
+		render, err := GetCellRenderer("fake=>")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(render("a")).To(Equal(">a"))
+	})
+
+	It("output with renderers", func() {
+		type fakeItem struct {
+			Name   string
+			Size   int
+			Status string
+		}
+
+		buffer := new(bytes.Buffer)
+		opt := &OutputOption{
+			Columns: "Name,Size:bytes,Status:status",
+			Writer:  buffer,
+		}
+		Expect(opt.OutputV2([]fakeItem{
+			{Name: "alice", Size: 2048, Status: "Running"},
+			{Name: "bob", Size: 1, Status: "Failed"},
+		})).To(Succeed())
+		Expect(StripANSI(buffer.String())).To(Equal(`Name  Size Status
+alice 2KiB Running
+bob   1B   Failed
+`))
+
+		opt.Columns = "Name:fake"
+		Expect(opt.OutputV2([]fakeItem{})).NotTo(Succeed())
+	})
+})
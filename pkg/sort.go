@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortKey is a field to sort the list
+type sortKey struct {
+	field      string
+	descending bool
+}
+
+// parseSortKeys parses the keys like: Name, -Age, Age:desc
+func (o *OutputOption) parseSortKeys() (keys []sortKey, err error) {
+	for _, text := range o.SortBy {
+		text = strings.TrimSpace(text)
+		key := sortKey{field: text}
+
+		switch {
+		case strings.HasPrefix(text, "-"):
+			key = sortKey{field: strings.TrimPrefix(text, "-"), descending: true}
+		case strings.HasSuffix(text, ":desc"):
+			key = sortKey{field: strings.TrimSuffix(text, ":desc"), descending: true}
+		case strings.HasSuffix(text, ":asc"):
+			key = sortKey{field: strings.TrimSuffix(text, ":asc")}
+		}
+
+		if key.field == "" {
+			err = fmt.Errorf("invalid sort key '%s'", text)
+			return
+		}
+		keys = append(keys, key)
+	}
+	return
+}
+
+// sortList returns a sorted copy of the list
+func (o *OutputOption) sortList(obj interface{}) (result interface{}, err error) {
+	result = obj
+	if len(o.SortBy) == 0 {
+		return
+	}
+
+	var keys []sortKey
+	if keys, err = o.parseSortKeys(); err != nil {
+		return
+	}
+
+	items := reflect.ValueOf(obj)
+
+	// get all the values at first, avoid the errors during sorting
+	values := make([][]reflect.Value, items.Len())
+	for i := range values {
+		for _, key := range keys {
+			var value reflect.Value
+			if value, err = ReflectFieldValue(items.Index(i), key.field); err != nil {
+				return
+			}
+			values[i] = append(values[i], indirectValue(value))
+		}
+	}
+
+	indexes := make([]int, items.Len())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		for k, key := range keys {
+			result := compareValues(values[indexes[i]][k], values[indexes[j]][k])
+			if key.descending {
+				result = -result
+			}
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+
+	resultSlice := reflect.MakeSlice(reflect.SliceOf(items.Type().Elem()), 0, items.Len())
+	for _, index := range indexes {
+		resultSlice = reflect.Append(resultSlice, items.Index(index))
+	}
+	result = resultSlice.Interface()
+	return
+}
+
+// pageList returns the items according to the offset and limit
+func (o *OutputOption) pageList(obj interface{}) interface{} {
+	if o.Offset <= 0 && o.Limit <= 0 {
+		return obj
+	}
+
+	items := reflect.ValueOf(obj)
+	start, end := o.Offset, items.Len()
+	if start < 0 {
+		start = 0
+	} else if start > end {
+		start = end
+	}
+	if o.Limit > 0 && start+o.Limit < end {
+		end = start + o.Limit
+	}
+	return items.Slice(start, end).Interface()
+}
+
+// compareValues compares two field values according to their type, the invalid value is the smallest one
+func compareValues(left, right reflect.Value) int {
+	switch {
+	case !left.IsValid() && !right.IsValid():
+		return 0
+	case !left.IsValid():
+		return -1
+	case !right.IsValid():
+		return 1
+	}
+
+	if left.Type() == timeType && right.Type() == timeType {
+		return compareInt64(left.Interface().(time.Time).UnixNano(), right.Interface().(time.Time).UnixNano())
+	}
+
+	switch left.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		if left.Kind() == right.Kind() {
+			return compareFloat(numberValue(left), numberValue(right))
+		}
+	}
+	return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sort and limit test", func() {
+	type fakeItem struct {
+		Name      string
+		Age       int
+		CreatedAt time.Time
+	}
+
+	var (
+		buffer *bytes.Buffer
+		opt    *OutputOption
+		items  []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns: "Name,Age",
+			Writer:  buffer,
+		}
+		items = []fakeItem{
+			{Name: "bob", Age: 9, CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)},
+			{Name: "alice", Age: 12, CreatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "tom", Age: 12, CreatedAt: time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)},
+		}
+	})
+
+	It("numeric instead of lexical", func() {
+		opt.SortBy = []string{"Age"}
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name  Age\nbob   9\nalice 12\ntom   12\n"))
+	})
+
+	It("multiple keys with descending order", func() {
+		opt.SortBy = []string{"-Age", "Name:desc"}
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name  Age\ntom   12\nalice 12\nbob   9\n"))
+	})
+
+	It("time with limit and offset", func() {
+		opt.SortBy = []string{"CreatedAt"}
+		opt.Offset = 1
+		opt.Limit = 1
+		opt.WithoutHeaders = true
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("bob 9\n"))
+		Expect(items[0].Name).To(Equal("bob"))
+	})
+
+	It("offset is out of range", func() {
+		opt.Format = JSONOutputFormat
+		opt.Offset = 5
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("[]"))
+	})
+
+	It("invalid sort key", func() {
+		opt.SortBy = []string{"Fake"}
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+		opt.SortBy = []string{"-"}
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+	})
+
+	It("flags", func() {
+		cmd := &cobra.Command{}
+		opt.SetFlagWithHeaders(cmd, "Name")
+		Expect(cmd.Flags().Parse([]string{"--sort-by", "Name,-Age", "--limit", "3", "--offset", "1"})).To(Succeed())
+		Expect(opt.SortBy).To(Equal([]string{"Name", "-Age"}))
+		Expect(opt.Limit).To(Equal(3))
+		Expect(opt.Offset).To(Equal(1))
+	})
+})
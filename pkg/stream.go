@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"reflect"
+)
+
+// DefaultStreamWindow is the default count of the look-ahead rows in the stream mode
+const DefaultStreamWindow = 50
+
+// OutputStream outputs the items one by one
+type OutputStream struct {
+	option *OutputOption
+	table  Table
+
+	filters  []*Filter
+	parseErr error
+
+	count   int
+	pending int
+	flushed bool
+}
+
+// NewStream creates a stream which accepts the items one by one
+func (o *OutputOption) NewStream() *OutputStream {
+	stream := &OutputStream{
+		option: o,
+		table:  CreateTableWithHeader(o.Writer, o.WithoutHeaders),
+	}
+	stream.table.ColorMode = o.Color
+	stream.table.AddHeader(o.GetHeaders()...)
+	if stream.parseErr = checkColorMode(o.Color); stream.parseErr == nil {
+		stream.filters, stream.parseErr = o.parseFilters()
+	}
+	return stream
+}
+
+// Write outputs an item, a table row might be held until the look-ahead window is full
+func (s *OutputStream) Write(item interface{}) (err error) {
+	o := s.option
+	if o.Writer == nil {
+		err = fmt.Errorf("no writer found")
+		return
+	}
+
+	var ok bool
+	if err = s.parseErr; err != nil {
+		return
+	} else if ok, err = matchFilters(s.filters, reflect.ValueOf(item)); !ok || err != nil {
+		return
+	}
+
+	var data []byte
+	switch o.Format {
+	case JSONOutputFormat:
+		if data, err = json.Marshal(item); err == nil {
+			data = append(data, '\n')
+		}
+	case YAMLOutputFormat:
+		if data, err = yaml.Marshal(item); err == nil && s.count > 0 {
+			data = append([]byte("---\n"), data...)
+		}
+	case TableOutputFormat, "":
+		if len(o.Columns) == 0 {
+			err = fmt.Errorf("no columns found")
+			return
+		}
+
+		var line []string
+		if line, err = o.getLine(reflect.ValueOf(item)); err != nil {
+			return
+		}
+		s.table.AddRow(line...)
+		if s.pending++; s.flushed || s.pending >= s.window() {
+			s.flush()
+		}
+	default:
+		err = fmt.Errorf("not support format %s", o.Format)
+	}
+
+	if err == nil {
+		s.count++
+		if len(data) > 0 {
+			_, err = o.Writer.Write(data)
+		}
+	}
+	return
+}
+
+// Close outputs all the held rows
+func (s *OutputStream) Close() (err error) {
+	if s.option.Writer == nil {
+		err = fmt.Errorf("no writer found")
+		return
+	}
+
+	switch s.option.Format {
+	case TableOutputFormat, "":
+		s.flush()
+	}
+	return
+}
+
+// flush renders the rows, the column widths of the first rows are kept for the following rows
+func (s *OutputStream) flush() {
+	s.table.Render()
+	if !s.flushed {
+		// the following rows are aligned with the printed ones, the long cells fit into the columns
+		for i, width := range s.table.layoutWidths() {
+			if width == 0 {
+				width = 1
+			}
+			s.table.SetColumnMaxWidth(i, width)
+		}
+		// the header was printed, the following rows are not the header
+		s.table.WithHeader = false
+	}
+	s.table.Clear()
+	s.pending = 0
+	s.flushed = true
+}
+
+func (s *OutputStream) window() int {
+	if s.option.StreamWindow > 0 {
+		return s.option.StreamWindow
+	}
+	return DefaultStreamWindow
+}
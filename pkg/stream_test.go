@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output stream test", func() {
+	type fakeItem struct {
+		Name string
+		Age  int
+	}
+
+	var (
+		buffer *bytes.Buffer
+		opt    *OutputOption
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns:      "Name,Age",
+			Writer:       buffer,
+			StreamWindow: 2,
+		}
+	})
+
+	It("table with look-ahead window", func() {
+		stream := opt.NewStream()
+		Expect(stream.Write(fakeItem{Name: "a", Age: 1})).To(Succeed())
+		Expect(buffer.String()).To(BeEmpty())
+
+		Expect(stream.Write(fakeItem{Name: "bob", Age: 10})).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name Age\na    1\nbob  10\n"))
+
+		Expect(stream.Write(fakeItem{Name: "c", Age: 2})).To(Succeed())
+		Expect(stream.Close()).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name Age\na    1\nbob  10\nc    2\n"))
+	})
+
+	It("table rows after the first window", func() {
+		stream := opt.NewStream()
+		stream.table.ColorMode = ColorAlways
+		stream.table.HeaderStyle = Style{Bold: true}
+		Expect(stream.Write(fakeItem{Name: "a", Age: 1})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "bob", Age: 10})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "charlie", Age: 1000})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "d", Age: 2})).To(Succeed())
+		Expect(stream.Close()).To(Succeed())
+		// only the first row is the header, the long cells fit into the columns of the first rows
+		Expect(buffer.String()).To(Equal("\x1b[1mName\x1b[0m \x1b[1mAge\x1b[0m\na    1\nbob  10\ncha… 10…\nd    2\n"))
+	})
+
+	It("ndjson with filter", func() {
+		opt.Format = JSONOutputFormat
+		opt.Filter = []string{"Name=b"}
+		stream := opt.NewStream()
+		Expect(stream.Write(fakeItem{Name: "a", Age: 1})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "bob", Age: 10})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "bill", Age: 2})).To(Succeed())
+		Expect(stream.Close()).To(Succeed())
+		Expect(buffer.String()).To(Equal(`{"Name":"bob","Age":10}
+{"Name":"bill","Age":2}
+`))
+	})
+
+	It("yaml documents", func() {
+		opt.Format = YAMLOutputFormat
+		stream := opt.NewStream()
+		Expect(stream.Write(fakeItem{Name: "a", Age: 1})).To(Succeed())
+		Expect(stream.Write(fakeItem{Name: "b", Age: 2})).To(Succeed())
+		Expect(buffer.String()).To(Equal("name: a\nage: 1\n---\nname: b\nage: 2\n"))
+	})
+
+	It("unknown format", func() {
+		opt.Format = "fake"
+		Expect(opt.NewStream().Write(fakeItem{})).NotTo(Succeed())
+	})
+})
@@ -1,9 +1,7 @@
 package pkg
 
 import (
-	"fmt"
 	"io"
-	"unicode/utf8"
 )
 
 // Table for console print
@@ -13,8 +11,41 @@ type Table struct {
 	ColumnWidths []int
 	ColumnAlign  []int
 	Separator    string
+	ColumnStyles []Style
+	HeaderStyle  Style
+	FooterStyle  Style
+	GroupStyle   Style
+
+	// MaxWidth is the max width of the table, it's the terminal width if it's zero. A negative value means no limit
+	MaxWidth int
+	// ColumnMaxWidths are the max widths of the columns, zero means no limit
+	ColumnMaxWidths []int
+	// ColumnOverflows decide how to fit the long cells into the columns, see also OverflowTruncate
+	ColumnOverflows []int
+	// ColumnPriorities decide which columns shrink first to fit the max width, the lower one shrinks first
+	ColumnPriorities []int
+
+	// Border is the border style, the table is borderless if it's nil
+	Border *BorderStyle
+	// RowSeparators draws the lines between the rows if the border style has it
+	RowSeparators bool
 
 	WithHeader bool
+	// ColorMode decides if the cell styles show, the default color mode is used if it's empty
+	ColorMode string
+
+	cellStyles map[cellPosition]Style
+	cellSpans  map[cellPosition]cellSpan
+	// footers is the count of the footer rows at the end of the rows
+	footers int
+	// groups are the indexes of the group heading rows
+	groups map[int]bool
+}
+
+// cellPosition is the row and column index of a cell
+type cellPosition struct {
+	row    int
+	column int
 }
 
 // CreateTable init a table object
@@ -35,11 +66,29 @@ func CreateTableWithHeader(out io.Writer, withoutHeader bool) (table Table) {
 // Clear removes all rows while preserving the layout
 func (t *Table) Clear() {
 	t.Rows = [][]string{}
+	t.footers = 0
+	t.groups = nil
 }
 
-// AddRow adds a new row to the table
+// AddRow adds a new row to the table, it's always above the footers
 func (t *Table) AddRow(col ...string) {
+	index := len(t.Rows) - t.footers
+	t.Rows = append(t.Rows[:index:index], append([][]string{col}, t.Rows[index:]...)...)
+}
+
+// AddGroup adds a group heading which takes all the columns, the rows added later belong to the group
+func (t *Table) AddGroup(title string) {
+	t.AddRow(title)
+	if t.groups == nil {
+		t.groups = map[int]bool{}
+	}
+	t.groups[len(t.Rows)-t.footers-1] = true
+}
+
+// AddFooter adds a footer row to the end of the table, e.g. the totals
+func (t *Table) AddFooter(col ...string) {
 	t.Rows = append(t.Rows, col)
+	t.footers++
 }
 
 // AddHeader adds a header to the table
@@ -52,34 +101,148 @@ func (t *Table) AddHeader(col ...string) {
 
 // Render render the table into byte array
 func (t *Table) Render() {
-	// lets figure out the max widths of each column
+	var columns int
 	for _, row := range t.Rows {
-		for ci, col := range row {
-			l := utf8.RuneCountInString(col)
-			t.ColumnWidths = ensureArrayCanContain(t.ColumnWidths, ci)
-			if l > t.ColumnWidths[ci] {
-				t.ColumnWidths[ci] = Lenf(col)
-			}
+		if len(row) > columns {
+			columns = len(row)
 		}
 	}
+	t.ColumnWidths = ensureArrayCanContain(t.ColumnWidths, columns-1)
+	owners := t.cellOwners(len(t.ColumnWidths))
+	border := t.getBorder()
+	colored := colorEnabled(t.ColorMode, t.Out)
 
-	out := t.Out
-	for _, row := range t.Rows {
-		lastColumn := len(row) - 1
-		for ci, col := range row {
-			if ci > 0 {
-				fmt.Fprint(out, t.Separator)
-			}
-			l := t.ColumnWidths[ci]
-			align := t.GetColumnAlign(ci)
-			if ci >= lastColumn && align != AlignCenter && align != AlignRight {
-				fmt.Fprint(out, col)
-			} else {
-				fmt.Fprint(out, Pad(col, " ", l, align))
+	// lets figure out the max widths of each column
+	var spanned []segment
+	for ri := range t.Rows {
+		for _, item := range rowSegments(owners, ri, len(t.ColumnWidths)) {
+			if !item.drawn {
+				continue
+			} else if item.columns > 1 {
+				spanned = append(spanned, item)
+			} else if l := Lenf(t.getCell(item.owner)); l > t.ColumnWidths[item.column] {
+				t.ColumnWidths[item.column] = l
 			}
 		}
-		fmt.Fprint(out, "\n")
 	}
+	// the last column of a span gets wider if the spanned cell does not fit
+	for _, item := range spanned {
+		if gap := Lenf(t.getCell(item.owner)) - border.segmentWidth(t.ColumnWidths, item); gap > 0 {
+			t.ColumnWidths[item.column+item.columns-1] += gap
+		}
+	}
+
+	if len(t.Rows) == 0 {
+		return
+	}
+	widths := t.layoutWidths()
+
+	t.renderLine(border, border.Top, owners, -1, 0, widths)
+	for ri := range t.Rows {
+		switch {
+		case ri == 0:
+		case ri == 1 && t.WithHeader, ri == len(t.Rows)-t.footers:
+			t.renderLine(border, border.HeaderSeparator, owners, ri-1, ri, widths)
+		case t.RowSeparators || t.groups[ri] || t.groups[ri-1]:
+			t.renderLine(border, border.RowSeparator, owners, ri-1, ri, widths)
+		}
+		t.renderRow(border, owners, ri, widths, colored)
+	}
+	t.renderLine(border, border.Bottom, owners, len(t.Rows)-1, len(t.Rows), widths)
+}
+
+// layoutWidths returns the column widths which fit the max widths
+func (t *Table) layoutWidths() (widths []int) {
+	widths = make([]int, len(t.ColumnWidths))
+	total := t.getBorder().borderWidth(len(widths))
+	for i, width := range t.ColumnWidths {
+		if max := t.GetColumnMaxWidth(i); max > 0 && width > max {
+			width = max
+		}
+		widths[i] = width
+		total += width
+	}
+
+	maxWidth := t.MaxWidth
+	if maxWidth == 0 {
+		maxWidth = TerminalWidth(t.Out)
+	}
+	for excess := total - maxWidth; maxWidth > 0 && excess > 0; {
+		i := t.nextShrinkColumn(widths)
+		if i < 0 {
+			break
+		}
+
+		shrink := widths[i] - minColumnWidth
+		if shrink > excess {
+			shrink = excess
+		}
+		widths[i] -= shrink
+		excess -= shrink
+	}
+	return
+}
+
+// nextShrinkColumn returns the column which has the lowest priority, the right one shrinks first
+func (t *Table) nextShrinkColumn(widths []int) (index int) {
+	index = -1
+	for i, width := range widths {
+		if width > minColumnWidth && (index < 0 || t.GetColumnPriority(i) <= t.GetColumnPriority(index)) {
+			index = i
+		}
+	}
+	return
+}
+
+func fitCell(cell string, width, overflow int) []string {
+	if Lenf(cell) <= width {
+		return []string{cell}
+	}
+
+	cell = StripANSI(cell)
+	switch overflow {
+	case OverflowWrap:
+		return Wrap(cell, width)
+	case OverflowTruncateMiddle:
+		return []string{TruncateMiddle(cell, width)}
+	default:
+		return []string{Truncate(cell, width)}
+	}
+}
+
+// SetColumnStyle sets the style for the given column index
+func (t *Table) SetColumnStyle(i int, style Style) {
+	for len(t.ColumnStyles) <= i {
+		t.ColumnStyles = append(t.ColumnStyles, Style{})
+	}
+	t.ColumnStyles[i] = style
+}
+
+// SetCellStyle sets the style for the given cell, it takes precedence over the column style
+func (t *Table) SetCellStyle(row, column int, style Style) {
+	if t.cellStyles == nil {
+		t.cellStyles = map[cellPosition]Style{}
+	}
+	t.cellStyles[cellPosition{row: row, column: column}] = style
+}
+
+// GetCellStyle returns the style of the given cell
+func (t *Table) GetCellStyle(row, column int) Style {
+	if style, ok := t.cellStyles[cellPosition{row: row, column: column}]; ok {
+		return style
+	}
+	switch {
+	case t.WithHeader && row == 0:
+		return t.HeaderStyle
+	case row >= len(t.Rows)-t.footers:
+		return t.FooterStyle
+	case t.groups[row]:
+		return t.GroupStyle
+	}
+	if column < len(t.ColumnStyles) {
+		return t.ColumnStyles[column]
+	}
+	return Style{}
 }
 
 // SetColumnsAligns sets the alignment of the columns
@@ -99,6 +262,42 @@ func (t *Table) SetColumnAlign(i int, align int) {
 	t.ColumnAlign[i] = align
 }
 
+// GetColumnMaxWidth returns the max width of the column, zero means no limit
+func (t *Table) GetColumnMaxWidth(i int) int {
+	t.ColumnMaxWidths = ensureArrayCanContain(t.ColumnMaxWidths, i)
+	return t.ColumnMaxWidths[i]
+}
+
+// SetColumnMaxWidth sets the max width for the given column index
+func (t *Table) SetColumnMaxWidth(i int, width int) {
+	t.ColumnMaxWidths = ensureArrayCanContain(t.ColumnMaxWidths, i)
+	t.ColumnMaxWidths[i] = width
+}
+
+// GetColumnOverflow returns how to fit the long cells into the column
+func (t *Table) GetColumnOverflow(i int) int {
+	t.ColumnOverflows = ensureArrayCanContain(t.ColumnOverflows, i)
+	return t.ColumnOverflows[i]
+}
+
+// SetColumnOverflow sets how to fit the long cells into the given column, e.g. OverflowWrap
+func (t *Table) SetColumnOverflow(i int, overflow int) {
+	t.ColumnOverflows = ensureArrayCanContain(t.ColumnOverflows, i)
+	t.ColumnOverflows[i] = overflow
+}
+
+// GetColumnPriority returns the priority of the column
+func (t *Table) GetColumnPriority(i int) int {
+	t.ColumnPriorities = ensureArrayCanContain(t.ColumnPriorities, i)
+	return t.ColumnPriorities[i]
+}
+
+// SetColumnPriority sets the priority for the given column index, the lower one shrinks first
+func (t *Table) SetColumnPriority(i int, priority int) {
+	t.ColumnPriorities = ensureArrayCanContain(t.ColumnPriorities, i)
+	t.ColumnPriorities[i] = priority
+}
+
 func ensureArrayCanContain(array []int, idx int) []int {
 	diff := idx + 1 - len(array)
 	for i := 0; i < diff; i++ {
@@ -126,4 +126,271 @@ var _ = Describe("Table util test", func() {
 			Expect(buffer.String()).To(Equal(""))
 		})
 	})
+
+	Context("styles", func() {
+		AfterEach(func() {
+			Expect(SetColorMode(ColorAuto)).To(Succeed())
+		})
+
+		It("column and cell styles", func() {
+			Expect(SetColorMode(ColorAlways)).To(Succeed())
+
+			var buffer bytes.Buffer
+			table := CreateTableWithHeader(&buffer, false)
+			table.HeaderStyle = Style{Bold: true}
+			table.SetColumnStyle(1, Style{Foreground: ColorRed})
+			table.SetCellStyle(2, 1, Style{Foreground: ColorGreen, Background: ColorBlack, Dim: true})
+			table.AddHeader("name", "status")
+			table.AddRow("a", "Failed")
+			table.AddRow("bob", "OK")
+			table.Render()
+			Expect(buffer.String()).To(Equal("\x1b[1mname\x1b[0m \x1b[1mstatus\x1b[0m\n" +
+				"a    \x1b[31mFailed\x1b[0m\n" +
+				"bob  \x1b[2;32;40mOK\x1b[0m\n"))
+			Expect(StripANSI(buffer.String())).To(Equal("name status\na    Failed\nbob  OK\n"))
+		})
+
+		It("never", func() {
+			Expect(SetColorMode(ColorNever)).To(Succeed())
+
+			var buffer bytes.Buffer
+			table := CreateTable(&buffer)
+			table.SetColumnStyle(0, Style{Bold: true})
+			table.AddRow("a", "b")
+			table.Render()
+			Expect(buffer.String()).To(Equal("a b\n"))
+		})
+
+		It("auto depends on the writer", func() {
+			Expect(SetColorMode(ColorAuto)).To(Succeed())
+			Expect(colorEnabled("", new(bytes.Buffer))).To(BeFalse())
+
+			var buffer bytes.Buffer
+			table := CreateTable(&buffer)
+			table.SetColumnStyle(0, Style{Bold: true})
+			table.AddRow("a", RenderStatus("Running"))
+			table.Render()
+			Expect(buffer.String()).To(Equal("a Running\n"))
+
+			Expect(SetColorMode(ColorAlways)).To(Succeed())
+			Expect(colorEnabled("", new(bytes.Buffer))).To(BeTrue())
+		})
+
+		It("color mode of the table and the option", func() {
+			Expect(SetColorMode(ColorNever)).To(Succeed())
+
+			var buffer bytes.Buffer
+			table := CreateTable(&buffer)
+			table.ColorMode = ColorAlways
+			table.SetColumnStyle(0, Style{Bold: true})
+			table.AddRow("a")
+			table.Render()
+			Expect(buffer.String()).To(Equal("\x1b[1ma\x1b[0m\n"))
+
+			Expect(SetColorMode(ColorAuto)).To(Succeed())
+			items := []struct{ Name string }{{Name: "a"}}
+			opt := &OutputOption{
+				Columns:       "Name",
+				Color:         ColorAlways,
+				Writer:        &buffer,
+				CellRenderMap: map[string]RenderCell{"Name": Style{Bold: true}.render},
+			}
+			buffer.Reset()
+			Expect(opt.OutputV2(items)).To(Succeed())
+			Expect(buffer.String()).To(Equal("Name\n\x1b[1ma\x1b[0m\n"))
+			// the option does not change the default color mode
+			Expect(colorMode).To(Equal(ColorAuto))
+
+			// the separated values and markdown never have the escape sequences
+			for _, format := range []string{CSVOutputFormat, TSVOutputFormat, MarkdownOutputFormat} {
+				buffer.Reset()
+				opt.Format = format
+				Expect(opt.OutputV2(items)).To(Succeed())
+				Expect(buffer.String()).NotTo(ContainSubstring("\x1b"))
+			}
+		})
+
+		It("invalid mode", func() {
+			Expect(SetColorMode("fake")).NotTo(Succeed())
+			Expect((&OutputOption{Color: "fake", Columns: "Name", Writer: &bytes.Buffer{}}).OutputV2([]string{})).NotTo(Succeed())
+		})
+	})
+
+	Context("layout", func() {
+		var (
+			buffer bytes.Buffer
+			table  Table
+		)
+
+		BeforeEach(func() {
+			buffer.Reset()
+			table = CreateTable(&buffer)
+			table.AddRow("name", "description")
+			table.AddRow("a", "a long description of the item")
+		})
+
+		It("no limit", func() {
+			table.Render()
+			Expect(buffer.String()).To(Equal("name description\na    a long description of the item\n"))
+		})
+
+		It("truncate the end", func() {
+			table.SetColumnMaxWidth(1, 12)
+			table.Render()
+			Expect(buffer.String()).To(Equal("name description\na    a long desc…\n"))
+		})
+
+		It("truncate the middle", func() {
+			table.SetColumnMaxWidth(1, 12)
+			table.SetColumnOverflow(1, OverflowTruncateMiddle)
+			table.Render()
+			Expect(buffer.String()).To(Equal("name description\na    a long… item\n"))
+		})
+
+		It("wrap", func() {
+			table.MaxWidth = 17
+			table.SetColumnOverflow(1, OverflowWrap)
+			table.AddRow("b", "short")
+			table.Render()
+			Expect(buffer.String()).To(Equal(`name description
+a    a long
+     description
+     of the item
+b    short
+`))
+		})
+
+		It("priority", func() {
+			table.AddRow("a-long-name", "short")
+			table.SetColumnPriority(0, -1)
+			table.MaxWidth = 30
+			table.Render()
+			Expect(buffer.String()).To(Equal(`name description
+a    a long description of th…
+a-l… short
+`))
+		})
+	})
+
+	Context("borders", func() {
+		var (
+			buffer bytes.Buffer
+			table  Table
+		)
+
+		BeforeEach(func() {
+			buffer.Reset()
+			table = CreateTableWithHeader(&buffer, false)
+			table.AddHeader("group", "name", "size")
+			table.AddRow("a", "first", "1")
+			table.AddRow("", "second", "22")
+			table.SetColumnAlign(2, AlignRight)
+		})
+
+		It("ascii", func() {
+			table.Border = BorderStyles["ascii"]
+			table.Render()
+			Expect(buffer.String()).To(Equal(`+-------+--------+------+
+| group | name   | size |
++-------+--------+------+
+| a     | first  |    1 |
+|       | second |   22 |
++-------+--------+------+
+`))
+		})
+
+		It("markdown", func() {
+			table.Border = BorderStyles["markdown"]
+			table.Render()
+			Expect(buffer.String()).To(Equal(`| group | name   | size |
+|-------|--------|------|
+| a     | first  |    1 |
+|       | second |   22 |
+`))
+		})
+
+		It("spans with row separators", func() {
+			table.Border = BorderStyles["light"]
+			table.RowSeparators = true
+			table.AddRow("total of all the items", "", "23")
+			table.SetCellSpan(1, 0, 1, 2)
+			table.SetCellSpan(3, 0, 2, 1)
+			table.Render()
+			Expect(buffer.String()).To(Equal(`┌───────┬────────────────┬──────┐
+│ group │ name           │ size │
+├───────┼────────────────┼──────┤
+│ a     │ first          │    1 │
+│       ├────────────────┼──────┤
+│       │ second         │   22 │
+├───────┴────────────────┼──────┤
+│ total of all the items │   23 │
+└────────────────────────┴──────┘
+`))
+		})
+
+		It("style names", func() {
+			Expect(GetBorderStyleNames()).To(Equal([]string{"ascii", "heavy", "light", "markdown", "none", "rounded"}))
+			style, err := GetBorderStyle("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(style).To(Equal(BorderNone))
+			_, err = GetBorderStyle("fake")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("output option", func() {
+			opt := &OutputOption{Columns: "Name", TableStyle: "rounded", Writer: &buffer}
+			Expect(opt.OutputV2([]struct{ Name string }{{Name: "a"}})).To(Succeed())
+			Expect(buffer.String()).To(Equal("╭──────╮\n│ Name │\n├──────┤\n│ a    │\n╰──────╯\n"))
+
+			opt.TableStyle = "fake"
+			Expect(opt.OutputV2([]struct{ Name string }{})).NotTo(Succeed())
+		})
+	})
+
+	Context("footers and groups", func() {
+		It("borderless", func() {
+			var buffer bytes.Buffer
+			table := CreateTableWithHeader(&buffer, false)
+			table.SetColumnAlign(1, AlignRight)
+			table.AddHeader("name", "size")
+			table.AddFooter("total", "33")
+			table.AddGroup("group one")
+			table.AddRow("a", "1")
+			table.AddRow("b", "22")
+			table.AddGroup("two")
+			table.AddRow("c", "10")
+			table.Render()
+			Expect(buffer.String()).To(Equal(`name  size
+group one
+a        1
+b       22
+two
+c       10
+total   33
+`))
+		})
+
+		It("styles", func() {
+			Expect(SetColorMode(ColorAlways)).To(Succeed())
+			defer func() {
+				_ = SetColorMode(ColorAuto)
+			}()
+
+			var buffer bytes.Buffer
+			table := CreateTable(&buffer)
+			table.FooterStyle = Style{Bold: true}
+			table.GroupStyle = Style{Dim: true}
+			table.AddGroup("g")
+			table.AddRow("a")
+			table.AddFooter("t")
+			table.Render()
+			Expect(buffer.String()).To(Equal("\x1b[2mg\x1b[0m\na\n\x1b[1mt\x1b[0m\n"))
+
+			table.Clear()
+			buffer.Reset()
+			table.AddRow("b")
+			table.Render()
+			Expect(buffer.String()).To(Equal("b\n"))
+		})
+	})
 })
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// tableTagName is the name of the struct tag which declares a table column, e.g. `table:"NAME,align=right,renderer=age,wide"`
+const tableTagName = "table"
+
+// tableAligns are the alignments which could be used in the struct tag
+var tableAligns = map[string]int{"left": AlignLeft, "center": AlignCenter, "right": AlignRight}
+
+// TableColumn is a table column which is declared by the struct tag of a field
+type TableColumn struct {
+	// Field is the name of the struct field
+	Field string
+	// Label is the header label, it's the field name if it's empty in the tag
+	Label string
+	// Align is the alignment of the column
+	Align int
+	// Renderer is the cell renderer, e.g. age, truncate=20
+	Renderer string
+	// Wide means the column only shows in the wide format
+	Wide bool
+}
+
+// ParseTableColumns returns the columns which are declared by the struct tags of a type. The type could be
+// a struct, or a pointer, slice or array of it. The fields without the tag or with the tag "-" are ignored
+func ParseTableColumns(t reflect.Type) (columns []TableColumn, err error) {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(tableTagName)
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		var column TableColumn
+		if column, err = parseTableTag(field.Name, tag); err != nil {
+			return
+		}
+		columns = append(columns, column)
+	}
+	return
+}
+
+// parseTableTag parses the struct tag of a field, e.g. NAME,align=right,renderer=age,wide
+func parseTableTag(field, tag string) (column TableColumn, err error) {
+	items := strings.Split(tag, ",")
+	column = TableColumn{Field: field, Label: strings.TrimSpace(items[0])}
+	if column.Label == "" {
+		column.Label = field
+	}
+
+	for _, item := range items[1:] {
+		key, value := strings.TrimSpace(item), ""
+		if index := strings.Index(key, "="); index >= 0 {
+			key, value = key[:index], key[index+1:]
+		}
+
+		switch key {
+		case "align":
+			var ok bool
+			if column.Align, ok = tableAligns[value]; !ok {
+				err = fmt.Errorf("invalid align '%s' in the table tag of the field %s", value, field)
+			}
+		case "renderer":
+			if _, err = GetCellRenderer(value); err != nil {
+				err = fmt.Errorf("invalid renderer in the table tag of the field %s, error: %v", field, err)
+			}
+			column.Renderer = value
+		case "wide":
+			column.Wide = true
+		default:
+			err = fmt.Errorf("unknown option '%s' in the table tag of the field %s", item, field)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// SetTableColumns sets the columns, wide columns, header labels, alignments and renderers from the struct tags
+// of the object type, the existing header labels and alignments are kept
+func (o *OutputOption) SetTableColumns(obj interface{}) (err error) {
+	var columns []TableColumn
+	if columns, err = ParseTableColumns(reflect.TypeOf(obj)); err != nil || len(columns) == 0 {
+		return
+	}
+
+	if o.HeaderLabels == nil {
+		o.HeaderLabels = map[string]string{}
+	}
+	if o.ColumnAligns == nil {
+		o.ColumnAligns = map[string]int{}
+	}
+
+	var normal, wide []string
+	for _, column := range columns {
+		col := column.Field
+		if column.Renderer != "" {
+			col += ":" + column.Renderer
+		}
+		if column.Wide {
+			wide = append(wide, col)
+		} else {
+			normal = append(normal, col)
+		}
+
+		if _, ok := o.HeaderLabels[column.Field]; !ok && column.Label != column.Field {
+			o.HeaderLabels[column.Field] = column.Label
+		}
+		if _, ok := o.ColumnAligns[column.Field]; !ok && column.Align != AlignLeft {
+			o.ColumnAligns[column.Field] = column.Align
+		}
+	}
+	o.Columns = strings.Join(normal, ",")
+	o.WideColumns = strings.Join(wide, ",")
+	return
+}
+
+// SetFlagWithTable sets the flags of output, the default columns come from the struct tags of the object type
+func (o *OutputOption) SetFlagWithTable(cmd *cobra.Command, obj interface{}) {
+	if err := o.SetTableColumns(obj); err != nil {
+		cmd.PrintErrf("cannot parse the table tags for sub-command %s, error: %v\n", cmd.Name(), err)
+	}
+	o.SetFlagWithHeaders(cmd, o.Columns)
+}
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table tag test", func() {
+	type fakeItem struct {
+		Name        string `table:"NAME"`
+		Size        int    `table:"SIZE,align=right,renderer=bytes"`
+		Description string `table:",wide,renderer=truncate=8"`
+		Secret      string `table:"-"`
+		Internal    string
+	}
+
+	var (
+		opt    *OutputOption
+		buffer *bytes.Buffer
+		items  []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{Writer: buffer}
+		items = []fakeItem{
+			{Name: "a", Size: 1, Description: "a long description", Secret: "fake"},
+			{Name: "bbb", Size: 2048},
+		}
+	})
+
+	It("parse the columns", func() {
+		columns, err := ParseTableColumns(reflect.TypeOf(items))
+		Expect(err).To(BeNil())
+		Expect(columns).To(Equal([]TableColumn{
+			{Field: "Name", Label: "NAME"},
+			{Field: "Size", Label: "SIZE", Align: AlignRight, Renderer: "bytes"},
+			{Field: "Description", Label: "Description", Renderer: "truncate=8", Wide: true},
+		}))
+
+		columns, err = ParseTableColumns(reflect.TypeOf(""))
+		Expect(err).To(BeNil())
+		Expect(columns).To(BeEmpty())
+	})
+
+	It("invalid tags", func() {
+		_, err := ParseTableColumns(reflect.TypeOf(struct {
+			Name string `table:"NAME,align=top"`
+		}{}))
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTableColumns(reflect.TypeOf(struct {
+			Name string `table:"NAME,renderer=fake"`
+		}{}))
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTableColumns(reflect.TypeOf(struct {
+			Name string `table:"NAME,fake"`
+		}{}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("set the table columns", func() {
+		opt.HeaderLabels = map[string]string{"Name": "Title"}
+		err := opt.SetTableColumns(items)
+		Expect(err).To(BeNil())
+		Expect(opt.Columns).To(Equal("Name,Size:bytes"))
+		Expect(opt.WideColumns).To(Equal("Description:truncate=8"))
+		Expect(opt.HeaderLabels).To(Equal(map[string]string{"Name": "Title", "Size": "SIZE"}))
+		Expect(opt.ColumnAligns).To(Equal(map[string]int{"Size": AlignRight}))
+	})
+
+	It("set the flags", func() {
+		cmd := &cobra.Command{}
+		opt.SetFlagWithTable(cmd, fakeItem{})
+		Expect(cmd.Flags().Lookup("columns").DefValue).To(Equal("Name,Size:bytes"))
+	})
+
+	It("output with the columns from the tags", func() {
+		err := opt.OutputV2(items)
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal(`NAME SIZE
+a      1B
+bbb  2KiB
+`))
+		Expect(opt.Columns).To(BeEmpty())
+		Expect(opt.HeaderLabels).To(BeNil())
+		Expect(opt.ColumnAligns).To(BeNil())
+
+		opt.HeaderLabels = map[string]string{"Name": "Title"}
+		opt.ColumnAligns = map[string]int{"Name": AlignRight}
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(opt.HeaderLabels).To(Equal(map[string]string{"Name": "Title"}))
+		Expect(opt.ColumnAligns).To(Equal(map[string]int{"Name": AlignRight}))
+	})
+
+	It("output in the wide format", func() {
+		Expect(opt.SetTableColumns(items)).To(Succeed())
+		opt.Format = WideOutputFormat
+		err := opt.OutputV2(items)
+		Expect(err).To(BeNil())
+		Expect(buffer.String()).To(Equal("NAME SIZE Description\n" +
+			"a      1B a long …\n" +
+			"bbb  2KiB \n"))
+	})
+
+	It("no columns without the tags", func() {
+		err := opt.OutputV2([]struct{ Name string }{{Name: "a"}})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// parseOutputFormat splits the format and its argument, e.g. jsonpath={.name}
+func parseOutputFormat(format string) (name, arg string) {
+	if index := strings.Index(format, "="); index >= 0 {
+		return format[:index], format[index+1:]
+	}
+	return format, ""
+}
+
+func (o *OutputOption) outputGoTemplate(obj interface{}, format, text string) (err error) {
+	if format == GoTemplateFileOutputFormat {
+		var data []byte
+		if data, err = ioutil.ReadFile(text); err != nil {
+			err = fmt.Errorf("cannot read the template file %s, error: %v", text, err)
+			return
+		}
+		text = string(data)
+	}
+
+	if text == "" {
+		err = fmt.Errorf("template is required by the format %s", format)
+		return
+	}
+
+	var tpl *template.Template
+	if tpl, err = template.New("output").Parse(text); err != nil {
+		err = fmt.Errorf("invalid template, error: %v", err)
+		return
+	}
+	err = tpl.Execute(o.Writer, obj)
+	return
+}
+
+func (o *OutputOption) outputJSONPath(obj interface{}, text string) (err error) {
+	if text == "" {
+		err = fmt.Errorf("template is required by the format %s", JSONPathOutputFormat)
+		return
+	}
+
+	var path *JSONPath
+	if path, err = ParseJSONPath(text); err == nil {
+		err = path.Execute(o.Writer, obj)
+	}
+	return
+}
+
+func (o *OutputOption) outputCustomColumns(obj interface{}, spec string) (err error) {
+	var headers []string
+	var paths []*JSONPath
+	for _, column := range strings.Split(spec, ",") {
+		items := strings.SplitN(column, ":", 2)
+		if len(items) != 2 || items[0] == "" || items[1] == "" {
+			err = fmt.Errorf("invalid custom column '%s', expected NAME:.path", column)
+			return
+		}
+
+		pathText := items[1]
+		if !strings.HasPrefix(pathText, "{") {
+			pathText = fmt.Sprintf("{%s}", pathText)
+		}
+
+		var path *JSONPath
+		if path, err = ParseJSONPath(pathText); err != nil {
+			return
+		}
+		path.AllowMissingKeys = true
+		headers = append(headers, items[0])
+		paths = append(paths, path)
+	}
+
+	table := CreateTableWithHeader(o.Writer, o.WithoutHeaders)
+	table.ColorMode = o.Color
+	table.AddHeader(headers...)
+	items := reflect.ValueOf(obj)
+	for i := 0; i < items.Len(); i++ {
+		row := make([]string, 0, len(paths))
+		for _, path := range paths {
+			buf := new(bytes.Buffer)
+			if err = path.Execute(buf, items.Index(i).Interface()); err != nil {
+				return
+			}
+
+			cell := buf.String()
+			if cell == "" {
+				cell = "<none>"
+			}
+			row = append(row, cell)
+		}
+		table.AddRow(row...)
+	}
+	table.Render()
+	return
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template output test", func() {
+	type fakeSpec struct {
+		Owner string
+		Tags  []string
+	}
+	type fakeItem struct {
+		Name string
+		Age  int
+		Spec fakeSpec
+	}
+
+	var (
+		buffer *bytes.Buffer
+		opt    *OutputOption
+		items  []fakeItem
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns: "Name,Age",
+			Writer:  buffer,
+		}
+		items = []fakeItem{{
+			Name: "alice",
+			Age:  12,
+			Spec: fakeSpec{Owner: "bob", Tags: []string{"a", "b"}},
+		}, {
+			Name: "tom",
+			Age:  8,
+		}}
+	})
+
+	It("go-template", func() {
+		opt.Format = "go-template={{range .}}{{.Name}}={{.Age}};{{end}}"
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("alice=12;tom=8;"))
+	})
+
+	It("go-template-file", func() {
+		f, err := ioutil.TempFile("", "template")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			_ = os.Remove(f.Name())
+		}()
+		_, _ = f.WriteString(`{{len .}}`)
+		_ = f.Close()
+
+		opt.Format = "go-template-file=" + f.Name()
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("2"))
+	})
+
+	It("invalid go-template", func() {
+		opt.Format = "go-template={{.Name"
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+	})
+
+	It("jsonpath", func() {
+		opt.Format = "jsonpath={[*].Name}"
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("alice tom"))
+	})
+
+	It("jsonpath with range", func() {
+		opt.Format = `jsonpath={range [*]}{.Name}{"\t"}{.Spec.Tags[-1:]}{"\n"}{end}`
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal("alice\tb\ntom\t\n"))
+	})
+
+	It("jsonpath with missing key", func() {
+		opt.Format = "jsonpath={[0].Fake}"
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+
+		opt.Format = "jsonpath={range [*]}{.Name}"
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+	})
+
+	It("custom-columns", func() {
+		opt.Format = "custom-columns=NAME:.Name,OWNER:.Spec.Owner,TAG:.Spec.Tags[0]"
+		Expect(opt.OutputV2(items)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`NAME  OWNER  TAG
+alice bob    a
+tom   <none> <none>
+`))
+	})
+
+	It("invalid custom-columns", func() {
+		opt.Format = "custom-columns=NAME"
+		Expect(opt.OutputV2(items)).NotTo(Succeed())
+	})
+})
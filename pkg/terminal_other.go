@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// terminalSize returns zero, the size comes from the environments COLUMNS and LINES on these platforms
+func terminalSize(file *os.File) (width, height int) {
+	return
+}
+
+// makeRaw is not supported on these platforms
+func makeRaw(file *os.File) (restore func(), err error) {
+	err = fmt.Errorf("not support the raw mode of the terminal on %s", runtime.GOOS)
+	return
+}
@@ -0,0 +1,45 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package pkg
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalSize returns the column and row count of the terminal
+func terminalSize(file *os.File) (width, height int) {
+	size, err := unix.IoctlGetWinsize(int(file.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return
+	}
+	return int(size.Col), int(size.Row)
+}
+
+// makeRaw puts the terminal into the raw mode, the input is available byte by byte without echo
+func makeRaw(file *os.File) (restore func(), err error) {
+	fd := int(file.Fd())
+	var termios *unix.Termios
+	if termios, err = unix.IoctlGetTermios(fd, ioctlReadTermios); err != nil {
+		return
+	}
+
+	origin := *termios
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err = unix.IoctlSetTermios(fd, ioctlWriteTermios, termios); err != nil {
+		return
+	}
+
+	restore = func() {
+		_ = unix.IoctlSetTermios(fd, ioctlWriteTermios, &origin)
+	}
+	return
+}
@@ -0,0 +1,509 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	keyUp        = "\x1b[A"
+	keyDown      = "\x1b[B"
+	keyPageUp    = "\x1b[5~"
+	keyPageDown  = "\x1b[6~"
+	keyHome      = "\x1b[H"
+	keyEnd       = "\x1b[F"
+	keyEscape    = "\x1b"
+	keyEnter     = "\r"
+	keyNewLine   = "\n"
+	keyBackspace = "\x7f"
+	keyCtrlH     = "\b"
+	keyCtrlC     = "\x03"
+)
+
+const (
+	clearScreen     = "\x1b[H\x1b[2J"
+	enterAltScreen  = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen  = "\x1b[?25h\x1b[?1049l"
+	defaultHeight   = 24
+	viewerHelpLines = 1
+)
+
+// TableViewer shows a table on the terminal interactively. It supports scrolling, searching,
+// sorting by a column, filtering and choosing the rows
+type TableViewer struct {
+	// Height is the line count of the screen, it's the terminal height if it's zero
+	Height int
+	// MultiSelect allows choosing multiple rows by the space key
+	MultiSelect bool
+
+	table  *Table
+	header []string
+	rows   [][]string
+
+	visible    []int
+	cursor     int
+	offset     int
+	selected   map[int]bool
+	sortColumn int
+	sortDesc   bool
+	search     string
+	filter     string
+	// editing is the search or filter text which is being typed
+	editing *string
+	quit    bool
+}
+
+// NewTableViewer creates a viewer of the table, the footers and group headings are not part of the rows
+func NewTableViewer(table *Table) (viewer *TableViewer) {
+	viewer = &TableViewer{
+		table:      table,
+		selected:   map[int]bool{},
+		sortColumn: -1,
+	}
+
+	for i, row := range table.Rows[:len(table.Rows)-table.footers] {
+		switch {
+		case i == 0 && table.WithHeader:
+			viewer.header = row
+		case !table.groups[i]:
+			viewer.rows = append(viewer.rows, row)
+		}
+	}
+	viewer.refresh()
+	return
+}
+
+// Run shows the table until the rows are chosen, nothing is chosen if it quits. It renders the table
+// if the stdin or the output is not a terminal, or the table fits the terminal
+func (v *TableViewer) Run() (chosen [][]string, err error) {
+	out, ok := v.table.Out.(*os.File)
+	if !ok || !isTerminal(out) || !isTerminal(os.Stdin) || len(v.table.Rows) < v.getHeight() {
+		v.table.Render()
+		return
+	}
+
+	var restore func()
+	if restore, err = makeRaw(os.Stdin); err != nil {
+		// fall back to the plain table
+		v.table.Render()
+		err = nil
+		return
+	}
+	defer restore()
+
+	fmt.Fprint(out, enterAltScreen)
+	defer fmt.Fprint(out, leaveAltScreen)
+	return v.run(os.Stdin, out)
+}
+
+// run handles the keys from the input until the rows are chosen or it quits
+func (v *TableViewer) run(in io.Reader, out io.Writer) (chosen [][]string, err error) {
+	reader := bufio.NewReader(in)
+	for {
+		v.draw(out)
+
+		var key string
+		if key, err = readKey(reader); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		if v.handleKey(key) {
+			break
+		}
+	}
+
+	if !v.quit {
+		chosen = v.getChosen()
+	}
+	return
+}
+
+// readKey reads a key, it's a rune or an escape sequence like the arrow keys
+func readKey(reader *bufio.Reader) (key string, err error) {
+	var r rune
+	if r, _, err = reader.ReadRune(); err != nil {
+		return
+	} else if r != '\x1b' || reader.Buffered() == 0 {
+		key = string(r)
+		return
+	}
+
+	var next byte
+	if next, err = reader.ReadByte(); err != nil {
+		return
+	} else if next != '[' && next != 'O' {
+		err = reader.UnreadByte()
+		key = keyEscape
+		return
+	}
+
+	sequence := []byte{'\x1b', next}
+	for {
+		var b byte
+		if b, err = reader.ReadByte(); err != nil {
+			return
+		}
+		sequence = append(sequence, b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	key = string(sequence)
+	return
+}
+
+// handleKey changes the state by the key, it returns true if the viewer should exit
+func (v *TableViewer) handleKey(key string) (exit bool) {
+	if v.editing != nil {
+		v.editText(key)
+		return
+	}
+
+	switch key {
+	case keyUp, "k":
+		v.move(-1)
+	case keyDown, "j":
+		v.move(1)
+	case keyPageUp, "u":
+		v.move(-v.pageSize())
+	case keyPageDown, "d":
+		v.move(v.pageSize())
+	case keyHome, "g":
+		v.move(-len(v.visible))
+	case keyEnd, "G":
+		v.move(len(v.visible))
+	case "/":
+		v.search = ""
+		v.editing = &v.search
+	case "n":
+		v.findNext(1)
+	case "f":
+		v.filter = ""
+		v.editing = &v.filter
+		v.refresh()
+	case "s":
+		// none, the first column, ..., the last column, none
+		v.sortBy((v.sortColumn+2)%(v.columnCount()+1) - 1)
+	case "r":
+		v.sortDesc = !v.sortDesc
+		v.refresh()
+	case " ":
+		if v.MultiSelect && len(v.visible) > 0 {
+			row := v.visible[v.cursor]
+			v.selected[row] = !v.selected[row]
+			v.move(1)
+		}
+	case keyEnter, keyNewLine:
+		exit = true
+	case "q", keyEscape, keyCtrlC:
+		v.quit = true
+		exit = true
+	default:
+		if column, err := strconv.Atoi(key); err == nil && column > 0 && column <= v.columnCount() {
+			if v.sortColumn == column-1 {
+				v.sortDesc = !v.sortDesc
+			}
+			v.sortBy(column - 1)
+		}
+	}
+	return
+}
+
+// editText edits the search or filter text, the filter takes effect while typing
+func (v *TableViewer) editText(key string) {
+	isFilter := v.editing == &v.filter
+	switch key {
+	case keyEnter, keyNewLine:
+		v.editing = nil
+		if !isFilter {
+			v.findNext(0)
+		}
+		return
+	case keyEscape, keyCtrlC:
+		*v.editing = ""
+		v.editing = nil
+	case keyBackspace, keyCtrlH:
+		if runes := []rune(*v.editing); len(runes) > 0 {
+			*v.editing = string(runes[:len(runes)-1])
+		}
+	default:
+		if runes := []rune(key); len(runes) == 1 && unicode.IsPrint(runes[0]) {
+			*v.editing += key
+		}
+	}
+
+	if isFilter {
+		v.refresh()
+	}
+}
+
+// columnCount returns the count of the columns
+func (v *TableViewer) columnCount() (count int) {
+	count = len(v.header)
+	for _, row := range v.rows {
+		if len(row) > count {
+			count = len(row)
+		}
+	}
+	return
+}
+
+func (v *TableViewer) sortBy(column int) {
+	v.sortColumn = column
+	v.refresh()
+}
+
+// refresh filters and sorts the rows, the cursor stays in the visible rows
+func (v *TableViewer) refresh() {
+	v.visible = v.visible[:0]
+	filter := strings.ToLower(v.filter)
+	for i, row := range v.rows {
+		if filter == "" || rowContains(row, filter) {
+			v.visible = append(v.visible, i)
+		}
+	}
+
+	if column := v.sortColumn; column >= 0 {
+		sort.SliceStable(v.visible, func(i, j int) bool {
+			result := compareCells(getRowCell(v.rows[v.visible[i]], column), getRowCell(v.rows[v.visible[j]], column))
+			if v.sortDesc {
+				return result > 0
+			}
+			return result < 0
+		})
+	}
+	v.move(0)
+}
+
+// findNext moves the cursor to the next row which contains the search text, it starts from the cursor plus the step
+func (v *TableViewer) findNext(step int) {
+	search := strings.ToLower(v.search)
+	if search == "" {
+		return
+	}
+
+	for i := 0; i < len(v.visible); i++ {
+		index := (v.cursor + step + i) % len(v.visible)
+		if rowContains(v.rows[v.visible[index]], search) {
+			v.move(index - v.cursor)
+			return
+		}
+	}
+}
+
+// move moves the cursor, the rows scroll to keep the cursor on the screen
+func (v *TableViewer) move(delta int) {
+	v.cursor += delta
+	if v.cursor >= len(v.visible) {
+		v.cursor = len(v.visible) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+
+	if size := v.pageSize(); v.cursor >= v.offset+size {
+		v.offset = v.cursor - size + 1
+	}
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+}
+
+func (v *TableViewer) getHeight() (height int) {
+	if height = v.Height; height <= 0 {
+		if height = TerminalHeight(v.table.Out); height <= 0 {
+			height = defaultHeight
+		}
+	}
+	return
+}
+
+// pageSize returns the count of the rows on the screen
+func (v *TableViewer) pageSize() (size int) {
+	size = v.getHeight() - viewerHelpLines
+	if len(v.header) > 0 {
+		size--
+	}
+
+	border := v.table.getBorder()
+	for _, line := range []BorderLine{border.Top, border.HeaderSeparator, border.Bottom} {
+		if line.Fill != "" {
+			size--
+		}
+	}
+
+	if size < 1 {
+		size = 1
+	}
+	return
+}
+
+// draw renders the rows on the screen and the status line at the bottom
+func (v *TableViewer) draw(out io.Writer) {
+	// the buffer has the same color as the screen
+	buffer := &terminalBuffer{terminal: out}
+	page := CreateTableWithHeader(buffer, len(v.header) == 0)
+	page.ColorMode = v.table.ColorMode
+	page.Border = v.table.Border
+	page.Separator = v.table.Separator
+	page.MaxWidth = v.table.MaxWidth
+	if page.MaxWidth == 0 {
+		page.MaxWidth = TerminalWidth(v.table.Out)
+	}
+	for i := 0; i < v.columnCount(); i++ {
+		page.SetColumnAlign(i+1, v.table.GetColumnAlign(i))
+		page.SetColumnMaxWidth(i+1, v.table.GetColumnMaxWidth(i))
+		page.SetColumnOverflow(i+1, v.table.GetColumnOverflow(i))
+		page.SetColumnPriority(i+1, v.table.GetColumnPriority(i))
+	}
+	// keep the column widths while scrolling
+	page.ColumnWidths = v.getWidths()
+
+	if len(v.header) > 0 {
+		header := append([]string{""}, v.header...)
+		if column := v.sortColumn; column >= 0 && column < len(v.header) {
+			header[column+1] += map[bool]string{false: " ↑", true: " ↓"}[v.sortDesc]
+		}
+		page.AddHeader(header...)
+	}
+
+	for i := v.offset; i < len(v.visible) && i < v.offset+v.pageSize(); i++ {
+		row := v.visible[i]
+		marker := " "
+		if v.selected[row] {
+			marker = "*"
+		}
+		if i == v.cursor {
+			marker = ">" + marker
+			for column := 0; column <= v.columnCount(); column++ {
+				page.SetCellStyle(len(page.Rows), column, Style{Bold: true})
+			}
+		} else {
+			marker = " " + marker
+		}
+		page.AddRow(append([]string{marker}, v.rows[row]...)...)
+	}
+	page.Render()
+
+	fmt.Fprint(out, clearScreen+strings.ReplaceAll(buffer.String(), "\n", "\r\n")+v.status())
+}
+
+// getWidths returns the widths of the marker column and all the columns
+func (v *TableViewer) getWidths() (widths []int) {
+	widths = make([]int, v.columnCount()+1)
+	widths[0] = 2
+	for i, cell := range v.header {
+		// there might be a sort indicator in the header
+		widths[i+1] = Lenf(cell) + 2
+	}
+	for _, row := range v.rows {
+		for i, cell := range row {
+			if l := Lenf(cell); l > widths[i+1] {
+				widths[i+1] = l
+			}
+		}
+	}
+	return
+}
+
+// status returns the help or the text which is being typed
+func (v *TableViewer) status() string {
+	switch {
+	case v.editing == &v.search:
+		return "/" + v.search
+	case v.editing == &v.filter:
+		return "filter: " + v.filter
+	}
+
+	var first int
+	if len(v.visible) > 0 {
+		first = v.offset + 1
+	}
+	last := v.offset + v.pageSize()
+	if last > len(v.visible) {
+		last = len(v.visible)
+	}
+
+	status := fmt.Sprintf("%d-%d of %d", first, last, len(v.visible))
+	if v.filter != "" {
+		status += fmt.Sprintf(" (filter: %s)", v.filter)
+	}
+	help := "↑/↓ move, / search, n next, f filter, 1-9 sort, r reverse, enter choose, q quit"
+	if v.MultiSelect {
+		help = "↑/↓ move, / search, n next, f filter, 1-9 sort, r reverse, space select, enter choose, q quit"
+	}
+	return status + " | " + help
+}
+
+// getChosen returns the selected rows, or the row under the cursor if nothing is selected
+func (v *TableViewer) getChosen() (rows [][]string) {
+	for _, row := range v.visible {
+		if v.selected[row] {
+			rows = append(rows, v.rows[row])
+		}
+	}
+	if len(rows) == 0 && len(v.visible) > 0 {
+		rows = append(rows, v.rows[v.visible[v.cursor]])
+	}
+	return
+}
+
+func rowContains(row []string, text string) bool {
+	for _, cell := range row {
+		if strings.Contains(strings.ToLower(StripANSI(cell)), text) {
+			return true
+		}
+	}
+	return false
+}
+
+func getRowCell(row []string, column int) string {
+	if column < len(row) {
+		return StripANSI(row[column])
+	}
+	return ""
+}
+
+// compareCells compares the cells as numbers if both of them are numbers
+func compareCells(left, right string) int {
+	leftNumber, leftErr := strconv.ParseFloat(strings.TrimSpace(left), 64)
+	rightNumber, rightErr := strconv.ParseFloat(strings.TrimSpace(right), 64)
+	if leftErr == nil && rightErr == nil {
+		return compareFloat(leftNumber, rightNumber)
+	}
+	return strings.Compare(left, right)
+}
+
+// SetInteractiveFlag sets the flag of the interactive table
+func (o *OutputOption) SetInteractiveFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.Interactive, "interactive", "", false,
+		"Show the table interactively if it's taller than the terminal, print the chosen rows when exiting")
+}
+
+// outputInteractive shows the table in the viewer, then prints the chosen rows
+func (o *OutputOption) outputInteractive(table *Table) (err error) {
+	var chosen [][]string
+	if chosen, err = NewTableViewer(table).Run(); err != nil || len(chosen) == 0 {
+		return
+	}
+
+	result := CreateTableWithHeader(o.Writer, o.WithoutHeaders)
+	result.Border = table.Border
+	result.ColorMode = table.ColorMode
+	result.AddHeader(o.GetHeaders()...)
+	for _, row := range chosen {
+		result.AddRow(row...)
+	}
+	result.Render()
+	return
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table viewer test", func() {
+	var (
+		buffer bytes.Buffer
+		table  Table
+		viewer *TableViewer
+	)
+
+	BeforeEach(func() {
+		buffer.Reset()
+		table = CreateTableWithHeader(&buffer, false)
+		table.AddHeader("name", "size")
+		for i := 1; i <= 10; i++ {
+			table.AddRow(fmt.Sprintf("item-%02d", i), fmt.Sprint(i%4))
+		}
+		table.AddFooter("total", "15")
+		viewer = NewTableViewer(&table)
+		viewer.Height = 5
+	})
+
+	run := func(keys ...string) [][]string {
+		var screen bytes.Buffer
+		chosen, err := viewer.run(strings.NewReader(strings.Join(keys, "")), &screen)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(screen.String()).To(ContainSubstring("\r\n"))
+		return chosen
+	}
+
+	It("render if it's not a terminal", func() {
+		chosen, err := viewer.Run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chosen).To(BeNil())
+		Expect(buffer.String()).To(HavePrefix("name    size\nitem-01 1\n"))
+	})
+
+	It("scroll", func() {
+		Expect(run(keyDown, "j", "k", keyEnter)).To(Equal([][]string{{"item-02", "2"}}))
+		Expect(run(keyPageDown, keyEnter)).To(Equal([][]string{{"item-05", "1"}}))
+		Expect(viewer.offset).To(Equal(2))
+		Expect(run("G", keyUp, keyEnter)).To(Equal([][]string{{"item-09", "1"}}))
+		Expect(run(keyHome, keyEnter)).To(Equal([][]string{{"item-01", "1"}}))
+	})
+
+	It("quit", func() {
+		Expect(run(keyDown, "q")).To(BeNil())
+	})
+
+	It("search", func() {
+		Expect(run("/", "0", "7", keyEnter, keyEnter)).To(Equal([][]string{{"item-07", "3"}}))
+		Expect(run("/", "1", keyEnter, "n", keyEnter)).To(Equal([][]string{{"item-10", "2"}}))
+	})
+
+	It("filter", func() {
+		Expect(run("f", "m", "-", "1", "x", keyBackspace, keyEnter, "j", keyEnter)).To(Equal([][]string{{"item-10", "2"}}))
+		Expect(viewer.visible).To(HaveLen(1))
+		Expect(run("f", "1", keyEscape, keyEnter)).To(Equal([][]string{{"item-01", "1"}}))
+		Expect(viewer.visible).To(HaveLen(10))
+	})
+
+	It("sort", func() {
+		Expect(run("2", keyEnter)).To(Equal([][]string{{"item-04", "0"}}))
+		Expect(run("2", keyEnter)).To(Equal([][]string{{"item-03", "3"}}))
+		Expect(run("s", "s", "s", "s", keyEnter)).To(Equal([][]string{{"item-01", "1"}}))
+		Expect(viewer.sortColumn).To(Equal(-1))
+
+		viewer = NewTableViewer(&table)
+		Expect(run("s", "r", keyEnter)).To(Equal([][]string{{"item-10", "2"}}))
+	})
+
+	It("multiple selection", func() {
+		viewer.MultiSelect = true
+		Expect(run(" ", " ", " ", keyUp, " ", keyEnter)).To(Equal([][]string{{"item-01", "1"}, {"item-02", "2"}}))
+	})
+
+	It("draw", func() {
+		var screen bytes.Buffer
+		viewer.move(3)
+		viewer.draw(&screen)
+		Expect(screen.String()).To(Equal(clearScreen +
+			"   name    size\r\n" +
+			"   item-02 2\r\n" +
+			"   item-03 3\r\n" +
+			">  item-04 0\r\n" +
+			"2-4 of 10 | ↑/↓ move, / search, n next, f filter, 1-9 sort, r reverse, enter choose, q quit"))
+	})
+
+	It("draw the cursor with the style", func() {
+		var screen bytes.Buffer
+		table.ColorMode = ColorAlways
+		viewer.move(3)
+		viewer.draw(&screen)
+		bold := Style{Bold: true}
+		Expect(screen.String()).To(ContainSubstring(bold.render("> ") + " " + bold.render("item-04") + " " + bold.render("0")))
+		Expect(screen.String()).To(ContainSubstring("   item-03 3\r\n"))
+	})
+
+	It("draw the sort indicator", func() {
+		var screen bytes.Buffer
+		viewer.handleKey("1")
+		viewer.handleKey("1")
+		viewer.draw(&screen)
+		Expect(screen.String()).To(HavePrefix(clearScreen + "   name ↓  size\r\n>  item-10 2\r\n"))
+	})
+})
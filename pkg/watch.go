@@ -0,0 +1,215 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultWatchInterval is the default interval between the fetches in the watch mode
+const DefaultWatchInterval = 2 * time.Second
+
+// FetchFunc returns the latest items to output, e.g. the list of the resources
+type FetchFunc func() (interface{}, error)
+
+const (
+	// WatchAdded is the event type of a new item
+	WatchAdded = "ADDED"
+	// WatchModified is the event type of a changed item
+	WatchModified = "MODIFIED"
+	// WatchDeleted is the event type of a removed item
+	WatchDeleted = "DELETED"
+)
+
+// WatchEvent is a change of an item in the watch mode, see also OutputOption.WatchEvents
+type WatchEvent struct {
+	Type   string      `json:"type" yaml:"type"`
+	Object interface{} `json:"object" yaml:"object"`
+}
+
+// watchedItem is an item which was printed in the watch mode
+type watchedItem struct {
+	data string
+	obj  interface{}
+}
+
+// watcher outputs the fetched items, it only outputs the changes
+type watcher struct {
+	option *OutputOption
+	// last is the last output of the whole list
+	last []byte
+	// items are the last items, the key comes from the watch key
+	items  map[string]watchedItem
+	stream *OutputStream
+}
+
+// SetWatchFlag sets the flags of the watch mode
+func (o *OutputOption) SetWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false,
+		"Watch the changes after outputting the items, the table is redrawn and only the changed items are printed in json or yaml")
+	cmd.Flags().DurationVarP(&o.WatchInterval, "watch-interval", "", DefaultWatchInterval,
+		"The interval between the fetches in the watch mode")
+	cmd.Flags().BoolVarP(&o.WatchEvents, "watch-events", "", false,
+		"Print the items with the event types in json or yaml, the removed items are only printed with this flag")
+}
+
+// OutputWatch outputs the items from the fetch function. In the watch mode, it fetches the items on the interval
+// until the context is done
+func (o *OutputOption) OutputWatch(ctx context.Context, fetch FetchFunc) (err error) {
+	var obj interface{}
+	if !o.Watch {
+		if obj, err = fetch(); err == nil {
+			err = o.OutputV2(obj)
+		}
+		return
+	}
+
+	interval := o.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w := &watcher{option: o, items: map[string]watchedItem{}}
+	for {
+		if obj, err = fetch(); err != nil {
+			return
+		}
+		if err = w.output(obj); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// output prints the changed items in json or yaml, redraws the whole list in the other formats
+func (w *watcher) output(obj interface{}) (err error) {
+	o := w.option
+	switch o.Format {
+	case JSONOutputFormat, YAMLOutputFormat:
+		return w.outputChanges(obj)
+	}
+
+	// the buffer has the same width and color as the terminal
+	file, _ := terminalFile(o.Writer)
+	buffer := &terminalBuffer{terminal: file}
+	option := *o
+	option.Writer = buffer
+	if err = option.OutputV2(obj); err != nil || bytes.Equal(buffer.Bytes(), w.last) {
+		return
+	}
+	w.last = buffer.Bytes()
+
+	if file != nil && isTerminal(file) {
+		// redraw in place
+		_, err = o.Writer.Write([]byte(clearScreen))
+	}
+	if err == nil {
+		_, err = o.Writer.Write(w.last)
+	}
+	return
+}
+
+// outputChanges prints the new and changed items, the items are identified by the watch key.
+// The removed items are printed as the deleted events if the events are enabled
+func (w *watcher) outputChanges(obj interface{}) (err error) {
+	o := w.option
+	if w.stream == nil {
+		// the items are filtered before writing into the stream
+		option := *o
+		option.Filter = nil
+		w.stream = option.NewStream()
+	}
+
+	items := indirectValue(reflect.ValueOf(obj))
+	switch items.Kind() {
+	case reflect.Slice, reflect.Array:
+		if obj, err = o.prepareList(items.Interface(), o.Format); err != nil {
+			return
+		}
+		items = reflect.ValueOf(obj)
+	default:
+		// a single object
+		items = reflect.ValueOf([]interface{}{obj})
+	}
+
+	current := map[string]bool{}
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+
+		var data []byte
+		if data, err = json.Marshal(item.Interface()); err != nil {
+			return
+		}
+		key := w.getKey(item, data)
+		current[key] = true
+
+		eventType := WatchAdded
+		if last, ok := w.items[key]; ok {
+			if last.data == string(data) {
+				continue
+			}
+			eventType = WatchModified
+		}
+		w.items[key] = watchedItem{data: string(data), obj: item.Interface()}
+
+		if err = w.write(eventType, item.Interface()); err != nil {
+			return
+		}
+	}
+
+	var removed []string
+	for key := range w.items {
+		if !current[key] {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		last := w.items[key]
+		delete(w.items, key)
+		if o.WatchEvents {
+			if err = w.write(WatchDeleted, last.obj); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// write prints the item, it's wrapped with the event type if the events are enabled
+func (w *watcher) write(eventType string, item interface{}) error {
+	if w.option.WatchEvents {
+		return w.stream.Write(WatchEvent{Type: eventType, Object: item})
+	}
+	return w.stream.Write(item)
+}
+
+// getKey returns the value of the watch key, it's the first column if there's no watch key.
+// The item itself is the key if the key is not available
+func (w *watcher) getKey(item reflect.Value, data []byte) string {
+	field := w.option.WatchKey
+	if field == "" {
+		if columns := w.option.getColumns(); len(columns) > 0 {
+			field = columns[0].field
+		}
+	}
+
+	if field != "" {
+		if value, err := ReflectFieldValueAsStringWithError(item, field); err == nil {
+			return value
+		}
+	}
+	return string(data)
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watch test", func() {
+	type fakeItem struct {
+		Name  string
+		Phase string
+	}
+
+	var (
+		buffer  *bytes.Buffer
+		opt     *OutputOption
+		ctx     context.Context
+		cancel  context.CancelFunc
+		results [][]fakeItem
+		fetch   FetchFunc
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		opt = &OutputOption{
+			Columns:       "Name,Phase",
+			Writer:        buffer,
+			Watch:         true,
+			WatchInterval: time.Millisecond,
+		}
+		ctx, cancel = context.WithCancel(context.Background())
+		results = [][]fakeItem{
+			{{Name: "a", Phase: "Pending"}},
+			{{Name: "a", Phase: "Pending"}},
+			{{Name: "a", Phase: "Running"}, {Name: "b", Phase: "Pending"}},
+		}
+
+		var count int
+		fetch = func() (obj interface{}, err error) {
+			obj = results[count]
+			if count++; count == len(results) {
+				cancel()
+			}
+			return
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("without watch", func() {
+		opt.Watch = false
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name Phase\na    Pending\n"))
+	})
+
+	It("redraw the table when it changes", func() {
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal("Name Phase\na    Pending\nName Phase\na    Running\nb    Pending\n"))
+	})
+
+	It("only the changed items in json", func() {
+		opt.Format = JSONOutputFormat
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`{"Name":"a","Phase":"Pending"}
+{"Name":"a","Phase":"Running"}
+{"Name":"b","Phase":"Pending"}
+`))
+	})
+
+	It("only the changed items in yaml with filters", func() {
+		opt.Format = YAMLOutputFormat
+		opt.Filter = []string{"Name==a"}
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal("name: a\nphase: Pending\n---\nname: a\nphase: Running\n"))
+	})
+
+	It("removed items with the events in json", func() {
+		opt.Format = JSONOutputFormat
+		opt.WatchEvents = true
+		results = [][]fakeItem{
+			{{Name: "a", Phase: "Pending"}, {Name: "b", Phase: "Pending"}},
+			{{Name: "a", Phase: "Running"}},
+		}
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`{"type":"ADDED","object":{"Name":"a","Phase":"Pending"}}
+{"type":"ADDED","object":{"Name":"b","Phase":"Pending"}}
+{"type":"MODIFIED","object":{"Name":"a","Phase":"Running"}}
+{"type":"DELETED","object":{"Name":"b","Phase":"Pending"}}
+`))
+	})
+
+	It("removed items without the events", func() {
+		opt.Format = JSONOutputFormat
+		results = [][]fakeItem{
+			{{Name: "a", Phase: "Pending"}, {Name: "b", Phase: "Pending"}},
+			{{Name: "a", Phase: "Pending"}},
+			{{Name: "a", Phase: "Pending"}, {Name: "b", Phase: "Pending"}},
+		}
+		Expect(opt.OutputWatch(ctx, fetch)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`{"Name":"a","Phase":"Pending"}
+{"Name":"b","Phase":"Pending"}
+{"Name":"b","Phase":"Pending"}
+`))
+	})
+
+	It("fetch error", func() {
+		Expect(opt.OutputWatch(ctx, func() (interface{}, error) {
+			return nil, fmt.Errorf("fake")
+		})).NotTo(Succeed())
+	})
+
+	It("flags", func() {
+		cmd := &cobra.Command{}
+		opt.SetWatchFlag(cmd)
+		Expect(cmd.ParseFlags([]string{"-w", "--watch-interval", "5s", "--watch-events"})).To(Succeed())
+		Expect(opt.Watch).To(BeTrue())
+		Expect(opt.WatchEvents).To(BeTrue())
+		Expect(opt.WatchInterval).To(Equal(5 * time.Second))
+	})
+})
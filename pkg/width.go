@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+const (
+	zeroWidthSpace     = '\u200b'
+	zeroWidthJoiner    = '\u200d'
+	wordJoiner         = '\u2060'
+	zeroWidthNoBreak   = '\ufeff'
+	textPresentation   = '\ufe0e'
+	emojiPresentation  = '\ufe0f'
+	regionalIndicatorA = '\U0001F1E6'
+	regionalIndicatorZ = '\U0001F1FF'
+)
+
+// graphemeExtendRanges are the ranges of the runes which extend the previous cluster besides the marks
+var graphemeExtendRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1160, Hi: 0x11ff, Stride: 1}, // Hangul Jungseong and Jongseong
+		{Lo: 0x200d, Hi: 0x200d, Stride: 1}, // zero width joiner
+		{Lo: 0xfe00, Hi: 0xfe0f, Stride: 1}, // variation selectors
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1}, // emoji modifiers
+		{Lo: 0xe0020, Hi: 0xe007f, Stride: 1}, // tags
+		{Lo: 0xe0100, Hi: 0xe01ef, Stride: 1}, // variation selectors supplement
+	},
+}
+
+// displayWidth returns the count of the terminal cells which the text takes, the ANSI escape sequences are ignored
+func displayWidth(text string) (count int) {
+	for _, cluster := range splitGraphemes(StripANSI(text)) {
+		count += graphemeWidth(cluster)
+	}
+	return
+}
+
+// splitGraphemes splits the text into the user-perceived characters. It covers the combining marks, variation
+// selectors, emoji modifiers, zero width joiner sequences, regional indicator pairs and the conjoining Hangul jamo
+func splitGraphemes(text string) (clusters []string) {
+	var (
+		start    int
+		prev     rune
+		regional int
+	)
+	for i, r := range text {
+		if i > 0 && !extendsGrapheme(prev, r, regional) {
+			clusters = append(clusters, text[start:i])
+			start, regional = i, 0
+		}
+		if isRegionalIndicator(r) {
+			regional++
+		}
+		prev = r
+	}
+	if start < len(text) {
+		clusters = append(clusters, text[start:])
+	}
+	return
+}
+
+// extendsGrapheme returns true if the rune belongs to the same cluster as the previous rune
+func extendsGrapheme(prev, r rune, regional int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner:
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		// a flag is a pair of regional indicators
+		return regional%2 == 1
+	}
+	return isGraphemeExtend(r)
+}
+
+// isGraphemeExtend returns true if the rune never starts a cluster
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, graphemeExtendRanges)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorA && r <= regionalIndicatorZ
+}
+
+// graphemeWidth returns the width of a cluster according to the East Asian Width of its first rune
+func graphemeWidth(cluster string) int {
+	var first rune
+	for _, first = range cluster {
+		break
+	}
+
+	switch {
+	case first == zeroWidthSpace || first == wordJoiner || first == zeroWidthNoBreak:
+		return 0
+	case unicode.IsControl(first) || isGraphemeExtend(first):
+		return 0
+	case isRegionalIndicator(first):
+		return 2
+	}
+
+	for _, r := range cluster {
+		switch r {
+		case emojiPresentation:
+			return 2
+		case textPresentation:
+			return 1
+		}
+	}
+
+	switch width.LookupRune(first).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
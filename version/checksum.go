@@ -0,0 +1,100 @@
+package version
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultChecksumFileName is the checksum file name which is generated by goreleaser
+const defaultChecksumFileName = "checksums.txt"
+
+// checksumTimeout is the timeout of getting the checksum file
+const checksumTimeout = 30 * time.Second
+
+// getChecksum returns the expected sha256 of the given file URL, it's empty if there is no checksum file.
+// It returns an error if the checksum file cannot be fetched, the upgrade should not go on without verifying
+func (o *SelfUpgradeOption) getChecksum(fileURL string) (checksum string, err error) {
+	checksumFile := o.ChecksumFileName
+	if checksumFile == "" {
+		checksumFile = defaultChecksumFileName
+	}
+
+	// the checksum file should be in the same directory with the file
+	index := strings.LastIndex(fileURL, "/")
+	if index < 0 {
+		return
+	}
+	checksumURL := fileURL[:index+1] + checksumFile
+
+	client := &http.Client{Transport: o.RoundTripper, Timeout: checksumTimeout}
+	var resp *http.Response
+	if resp, err = client.Get(checksumURL); err != nil {
+		err = fmt.Errorf("cannot get the checksum file from %s, error: %v", checksumURL, err)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		checksum = findChecksum(resp.Body, path.Base(fileURL))
+	case http.StatusNotFound:
+		// the release has no checksum file
+	default:
+		err = fmt.Errorf("cannot get the checksum file from %s, status code: %d", checksumURL, resp.StatusCode)
+	}
+	return
+}
+
+// findChecksum finds the checksum of a file from the content like 'sha256  filename'
+func findChecksum(reader io.Reader, fileName string) (checksum string) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == fileName {
+			checksum = strings.ToLower(fields[0])
+			break
+		}
+	}
+	return
+}
+
+// verifyChecksum makes sure the sha256 of the file is the expected one
+func verifyChecksum(filePath, checksum string) (err error) {
+	var f *os.File
+	if f, err = os.Open(filePath); err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		err = fmt.Errorf("checksum mismatch of %s, expected sha256:%s, got sha256:%s", filePath, checksum, actual)
+	}
+	return
+}
+
+// isWritable checks if current user has the write permission of the file
+func isWritable(filePath string) bool {
+	f, err := os.OpenFile(filePath, os.O_WRONLY, 0666)
+	if err != nil {
+		return !os.IsPermission(err)
+	}
+	_ = f.Close()
+	return true
+}
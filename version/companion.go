@@ -0,0 +1,153 @@
+package version
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/linuxsuren/cobra-extension/common"
+	"github.com/mitchellh/go-homedir"
+)
+
+// findCompanion returns the index of the companion file which matches the archive member name
+func (o *SelfUpgradeOption) findCompanion(name string) int {
+	name = strings.TrimPrefix(name, "./")
+	for i, companion := range o.Companions {
+		if strings.TrimPrefix(companion.Source, "./") == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// stagedCompanionDir returns the directory of the companion files after they were extracted
+func (o *SelfUpgradeOption) stagedCompanionDir(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-companions", o.Name))
+}
+
+// stagedCompanionPath returns the path of a companion file after it was extracted
+func (o *SelfUpgradeOption) stagedCompanionPath(dir string, index int) string {
+	return filepath.Join(o.stagedCompanionDir(dir), fmt.Sprintf("%d", index))
+}
+
+// installCompanions installs all the companion files, nothing will be changed if any of them failed.
+// The returned rollback function restores the previous files, the cleanup function removes the backups
+// and it must be called once the rollback is not needed anymore.
+func (o *SelfUpgradeOption) installCompanions(dir string) (rollback, cleanup func(), err error) {
+	defer func() {
+		_ = os.RemoveAll(o.stagedCompanionDir(dir))
+	}()
+
+	var backupDir string
+	if backupDir, err = ioutil.TempDir("", fmt.Sprintf("%s-backup", o.Name)); err != nil {
+		return
+	}
+	cleanup = func() {
+		_ = os.RemoveAll(backupDir)
+	}
+
+	// the original files which need to be restored, the value is empty if there's no original file
+	backups := map[string]string{}
+	rollback = func() {
+		for target, backup := range backups {
+			if backup == "" {
+				_ = os.Remove(target)
+			} else {
+				_ = copyFile(backup, target)
+			}
+		}
+	}
+	defer func() {
+		if err != nil {
+			rollback()
+			cleanup()
+		}
+	}()
+
+	// stage all the files next to the targets, make sure the rename is atomic
+	staged := map[string]string{}
+	defer func() {
+		for _, stagedFile := range staged {
+			_ = os.Remove(stagedFile)
+		}
+	}()
+	for i, companion := range o.Companions {
+		source := o.stagedCompanionPath(dir, i)
+		if _, err = os.Stat(source); err != nil {
+			err = fmt.Errorf("cannot find %s from the archive", companion.Source)
+			return
+		}
+
+		var target string
+		if target, err = homedir.Expand(companion.Target); err != nil {
+			return
+		}
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return
+		}
+
+		stagedFile := fmt.Sprintf("%s.%s-upgrade", target, o.Name)
+		if err = copyFile(source, stagedFile); err != nil {
+			return
+		}
+		staged[target] = stagedFile
+	}
+
+	for i, companion := range o.Companions {
+		target, _ := homedir.Expand(companion.Target)
+		if _, statErr := os.Stat(target); statErr == nil {
+			backup := filepath.Join(backupDir, fmt.Sprintf("%d", i))
+			if err = copyFile(target, backup); err != nil {
+				break
+			}
+			backups[target] = backup
+		} else {
+			backups[target] = ""
+		}
+
+		if err = os.Rename(staged[target], target); err != nil {
+			break
+		}
+		delete(staged, target)
+	}
+	return
+}
+
+// regenerateCompletion generates the completion scripts by the new binary file
+func (o *SelfUpgradeOption) regenerateCompletion(log common.Printer, binary string) {
+	if len(o.CompletionArgs) == 0 {
+		return
+	}
+
+	cmd := exec.Command(binary, o.CompletionArgs...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		log.PrintErr(fmt.Sprintf("cannot regenerate the completion scripts, error: %v, %s", err, string(output)))
+	}
+}
+
+func copyFile(source, target string) (err error) {
+	var sourceF, targetF *os.File
+	var info os.FileInfo
+	if sourceF, err = os.Open(source); err != nil {
+		return
+	}
+	defer func() {
+		_ = sourceF.Close()
+	}()
+	if info, err = sourceF.Stat(); err != nil {
+		return
+	}
+
+	if targetF, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode()); err != nil {
+		return
+	}
+	defer func() {
+		_ = targetF.Close()
+	}()
+	_, err = io.Copy(targetF, sourceF)
+	return
+}
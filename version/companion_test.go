@@ -0,0 +1,104 @@
+package version
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("companion files", func() {
+	var (
+		dir string
+		opt *SelfUpgradeOption
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "companion")
+		Expect(err).NotTo(HaveOccurred())
+
+		opt = &SelfUpgradeOption{
+			Name: "fake",
+			Companions: []CompanionFile{{
+				Source: "completion/fake.bash",
+				Target: filepath.Join(dir, "target", "fake.bash"),
+			}, {
+				Source: "./man/fake.1",
+				Target: filepath.Join(dir, "target", "fake.1"),
+			}},
+		}
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("find companion", func() {
+		Expect(opt.findCompanion("completion/fake.bash")).To(Equal(0))
+		Expect(opt.findCompanion("man/fake.1")).To(Equal(1))
+		Expect(opt.findCompanion("fake")).To(Equal(-1))
+	})
+
+	It("install all", func() {
+		for i := range opt.Companions {
+			staged := opt.stagedCompanionPath(dir, i)
+			Expect(os.MkdirAll(filepath.Dir(staged), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(staged, []byte("new"), 0644)).To(Succeed())
+		}
+
+		rollback, cleanup, err := opt.installCompanions(dir)
+		Expect(err).NotTo(HaveOccurred())
+		defer cleanup()
+		_, err = os.Stat(opt.stagedCompanionDir(dir))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		data, _ := ioutil.ReadFile(opt.Companions[1].Target)
+		Expect(string(data)).To(Equal("new"))
+
+		rollback()
+		_, err = os.Stat(opt.Companions[1].Target)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("keep the old files when missing one of them", func() {
+		staged := opt.stagedCompanionPath(dir, 0)
+		Expect(os.MkdirAll(filepath.Dir(staged), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(staged, []byte("new"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "target"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(opt.Companions[0].Target, []byte("old"), 0644)).To(Succeed())
+
+		_, _, err := opt.installCompanions(dir)
+		Expect(err).To(HaveOccurred())
+		data, _ := ioutil.ReadFile(opt.Companions[0].Target)
+		Expect(string(data)).To(Equal("old"))
+	})
+
+	It("fail to extract a truncated companion", func() {
+		content := make([]byte, 64*1024)
+		rand.New(rand.NewSource(1)).Read(content)
+
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		Expect(tarWriter.WriteHeader(&tar.Header{
+			Name:     "completion/fake.bash",
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		})).To(Succeed())
+		_, err := tarWriter.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+
+		tarFile := filepath.Join(dir, "fake.tar.gz")
+		Expect(ioutil.WriteFile(tarFile, buffer.Bytes()[:buffer.Len()/2], 0644)).To(Succeed())
+		Expect(opt.extractFiles(tarFile)).NotTo(Succeed())
+	})
+})
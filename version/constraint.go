@@ -0,0 +1,339 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Constraint is a range of the semantic versions, e.g. ">= 2.3", "^1.4", ">= 1.2, < 2 || ~3.1"
+type Constraint struct {
+	raw string
+	// the conditions in a group are AND, the groups are OR
+	groups [][]condition
+}
+
+// Requirement represents a version requirement of a component, e.g. requires server >= 2.3
+type Requirement struct {
+	// Name is the name of the component, e.g. server
+	Name string
+	// Constraint is the expected version range, e.g. >= 2.3
+	Constraint string
+	// GetVersion returns the actual version of the component
+	GetVersion func() (string, error)
+	// Hint is the suggestion when the requirement is not satisfied, there's a default one if it's empty
+	Hint string
+	// SkipDev skips the check if the version is empty or dev, e.g. a build without the version ldflags
+	SkipDev bool
+}
+
+// RequirementError indicates a requirement is not satisfied
+type RequirementError struct {
+	Name       string
+	Version    string
+	Constraint string
+	Hint       string
+}
+
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+	// the count of the specified parts, it's 2 for 1.4 and 1.x
+	parts int
+}
+
+type condition struct {
+	op  string
+	ver semver
+}
+
+// Error returns the message of the error
+func (e *RequirementError) Error() string {
+	return fmt.Sprintf("%s version %s does not satisfy the requirement '%s', %s",
+		e.Name, e.Version, e.Constraint, e.Hint)
+}
+
+// ParseConstraint parses the text of a version constraint
+func ParseConstraint(text string) (constraint *Constraint, err error) {
+	constraint = &Constraint{raw: text}
+	for _, group := range strings.Split(text, "||") {
+		var conditions []condition
+		for _, item := range splitConditions(group) {
+			var conds []condition
+			if conds, err = parseCondition(item); err != nil {
+				err = fmt.Errorf("invalid version constraint '%s', error: %v", text, err)
+				return
+			}
+			conditions = append(conditions, conds...)
+		}
+
+		if len(conditions) == 0 {
+			err = fmt.Errorf("invalid version constraint '%s', no condition found", text)
+			return
+		}
+		constraint.groups = append(constraint.groups, conditions)
+	}
+	return
+}
+
+// String returns the raw text of the constraint
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Check returns true if the version satisfies the constraint
+func (c *Constraint) Check(version string) (ok bool, err error) {
+	var ver semver
+	if ver, err = parseSemver(version); err != nil {
+		return
+	}
+
+	for _, group := range c.groups {
+		ok = true
+		for _, cond := range group {
+			if !cond.match(ver) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			break
+		}
+	}
+	return
+}
+
+// CheckRequirements checks all the requirements, name is the CLI name which is used to give the upgrade suggestion
+func CheckRequirements(name string, requirements ...Requirement) (err error) {
+	for _, requirement := range requirements {
+		var constraint *Constraint
+		if constraint, err = ParseConstraint(requirement.Constraint); err != nil {
+			return
+		}
+
+		var ver string
+		if ver, err = requirement.GetVersion(); err != nil {
+			err = fmt.Errorf("cannot get the version of %s, error: %v", requirement.Name, err)
+			return
+		}
+		if requirement.SkipDev && (ver == "" || ver == "dev") {
+			continue
+		}
+
+		var ok bool
+		if ok, err = constraint.Check(ver); err != nil {
+			err = fmt.Errorf("cannot check the version of %s, error: %v", requirement.Name, err)
+			return
+		} else if !ok {
+			hint := requirement.Hint
+			if hint == "" {
+				hint = fmt.Sprintf("please try to get a compatible version via '%s version upgrade'", name)
+			}
+			err = &RequirementError{
+				Name:       requirement.Name,
+				Version:    ver,
+				Constraint: requirement.Constraint,
+				Hint:       hint,
+			}
+			return
+		}
+	}
+	return
+}
+
+// NewRequirementsPreRunE returns a function which checks the requirements before running a command
+func NewRequirementsPreRunE(name string, requirements ...Requirement) func(*cobra.Command, []string) error {
+	return func(_ *cobra.Command, _ []string) error {
+		return CheckRequirements(name, requirements...)
+	}
+}
+
+// HostRequirement returns the requirement of the host CLI version, e.g. a plugin compatible with host ^1.4.
+// It's always satisfied by the dev builds of the host
+func HostRequirement(name, constraint string) Requirement {
+	return Requirement{
+		Name:       name,
+		Constraint: constraint,
+		SkipDev:    true,
+		GetVersion: func() (string, error) {
+			return GetVersion(), nil
+		},
+	}
+}
+
+// splitConditions splits the text by comma or space, and keeps the operator together with the version
+func splitConditions(text string) (items []string) {
+	var op string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		if strings.Trim(field, "<>=!~^") == "" {
+			// the operator and version were separated by spaces, e.g. '>= 2.3'
+			op += field
+			continue
+		}
+		items = append(items, op+field)
+		op = ""
+	}
+	if op != "" {
+		items = append(items, op)
+	}
+	return
+}
+
+func parseCondition(text string) (conditions []condition, err error) {
+	verText := strings.TrimLeft(text, "<>=!~^")
+	op := text[:len(text)-len(verText)]
+
+	var ver semver
+	if ver, err = parseSemver(verText); err != nil {
+		return
+	}
+
+	switch op {
+	case "^":
+		upper := semver{major: ver.major + 1, parts: 3}
+		if ver.major == 0 && ver.parts > 1 {
+			upper = semver{minor: ver.minor + 1, parts: 3}
+			if ver.minor == 0 && ver.parts > 2 {
+				upper = semver{patch: ver.patch + 1, parts: 3}
+			}
+		}
+		conditions = []condition{{op: ">=", ver: ver}, {op: "<", ver: upper}}
+	case "~":
+		upper := semver{major: ver.major, minor: ver.minor + 1, parts: 3}
+		if ver.parts == 1 {
+			upper = semver{major: ver.major + 1, parts: 3}
+		}
+		conditions = []condition{{op: ">=", ver: ver}, {op: "<", ver: upper}}
+	case "", "=", "==":
+		if ver.parts < 3 {
+			// a wildcard version, e.g. 1.x or 1.4
+			conditions = []condition{{op: ">=", ver: ver}, {op: "<", ver: ver.next()}}
+		} else {
+			conditions = []condition{{op: "==", ver: ver}}
+		}
+	case "!=", ">", ">=", "<", "<=":
+		conditions = []condition{{op: op, ver: ver}}
+	default:
+		err = fmt.Errorf("unknown operator '%s'", op)
+	}
+	return
+}
+
+func parseSemver(text string) (ver semver, err error) {
+	text = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(text), "v"), "V")
+	if index := strings.Index(text, "+"); index >= 0 {
+		// ignore the build metadata
+		text = text[:index]
+	}
+	if index := strings.Index(text, "-"); index >= 0 {
+		ver.pre = strings.Split(text[index+1:], ".")
+		text = text[:index]
+	}
+
+	parts := strings.Split(text, ".")
+	if text == "" || len(parts) > 3 {
+		err = fmt.Errorf("invalid version '%s'", text)
+		return
+	}
+
+	numbers := []*int{&ver.major, &ver.minor, &ver.patch}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		if *numbers[i], err = strconv.Atoi(part); err != nil || *numbers[i] < 0 {
+			err = fmt.Errorf("invalid version '%s'", text)
+			return
+		}
+		ver.parts = i + 1
+	}
+	return
+}
+
+// next returns the first version which is out of the wildcard range
+func (v semver) next() semver {
+	switch v.parts {
+	case 0:
+		return semver{major: int(^uint(0) >> 1), parts: 3}
+	case 1:
+		return semver{major: v.major + 1, parts: 3}
+	default:
+		return semver{major: v.major, minor: v.minor + 1, parts: 3}
+	}
+}
+
+func (v semver) compare(target semver) int {
+	if result := compareInt(v.major, target.major); result != 0 {
+		return result
+	}
+	if result := compareInt(v.minor, target.minor); result != 0 {
+		return result
+	}
+	if result := compareInt(v.patch, target.patch); result != 0 {
+		return result
+	}
+
+	// a pre-release version has lower precedence than the normal version
+	switch {
+	case len(v.pre) == 0 && len(target.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(target.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(target.pre); i++ {
+		left, leftErr := strconv.Atoi(v.pre[i])
+		right, rightErr := strconv.Atoi(target.pre[i])
+
+		var result int
+		switch {
+		case leftErr == nil && rightErr == nil:
+			result = compareInt(left, right)
+		case leftErr == nil:
+			result = -1
+		case rightErr == nil:
+			result = 1
+		default:
+			result = strings.Compare(v.pre[i], target.pre[i])
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return compareInt(len(v.pre), len(target.pre))
+}
+
+func (c condition) match(ver semver) bool {
+	result := ver.compare(c.ver)
+	switch c.op {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+func compareInt(left, right int) int {
+	switch {
+	case left > right:
+		return 1
+	case left < right:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,94 @@
+package version_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/linuxsuren/cobra-extension/version"
+)
+
+var _ = Describe("version constraint", func() {
+	DescribeTable("check",
+		func(constraint, ver string, expected bool) {
+			c, err := version.ParseConstraint(constraint)
+			Expect(err).NotTo(HaveOccurred())
+			ok, err := c.Check(ver)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("greater or equal", ">= 2.3", "v2.3.0", true),
+		Entry("greater or equal without space", ">=2.3", "2.2.9", false),
+		Entry("range", ">= 1.2, < 2", "1.9.9", true),
+		Entry("out of range", ">= 1.2 < 2", "2.0.0", false),
+		Entry("caret", "^1.4", "1.9.0", true),
+		Entry("caret with major", "^1.4", "2.0.0", false),
+		Entry("caret with zero major", "^0.4.1", "0.5.0", false),
+		Entry("tilde", "~1.4", "1.4.9", true),
+		Entry("tilde with minor", "~1.4", "1.5.0", false),
+		Entry("wildcard", "1.x", "1.8.0", true),
+		Entry("exact", "1.2.3", "1.2.4", false),
+		Entry("not equal", "!= 1.2.3", "1.2.4", true),
+		Entry("or", "^1.0 || ^3.0", "3.1.0", true),
+		Entry("pre-release", "< 1.0.0", "1.0.0-rc.1", true),
+		Entry("pre-release order", "> 1.0.0-alpha.1", "1.0.0-alpha.beta", true),
+	)
+
+	It("invalid constraint", func() {
+		_, err := version.ParseConstraint(">> 1.0")
+		Expect(err).To(HaveOccurred())
+		_, err = version.ParseConstraint("")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("invalid version", func() {
+		c, err := version.ParseConstraint(">= 1.0")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.Check("dev")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("requirements", func() {
+		server := version.Requirement{
+			Name:       "server",
+			Constraint: ">= 2.3",
+			GetVersion: func() (string, error) {
+				return "2.1.0", nil
+			},
+		}
+		err := version.CheckRequirements("jcli", server)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("jcli version upgrade"))
+		var reqErr *version.RequirementError
+		Expect(errors.As(err, &reqErr)).To(BeTrue())
+		Expect(reqErr.Version).To(Equal("2.1.0"))
+
+		version.SetVersion("v1.5.0")
+		preRunE := version.NewRequirementsPreRunE("jcli", version.HostRequirement("host", "^1.4"))
+		Expect(preRunE(&cobra.Command{}, nil)).To(Succeed())
+
+		version.SetVersion("v2.0.0")
+		Expect(preRunE(&cobra.Command{}, nil)).NotTo(Succeed())
+	})
+
+	It("dev host", func() {
+		defer version.SetVersion("")
+		preRunE := version.NewRequirementsPreRunE("jcli", version.HostRequirement("host", "^1.4"))
+		for _, ver := range []string{"", "dev"} {
+			version.SetVersion(ver)
+			Expect(preRunE(&cobra.Command{}, nil)).To(Succeed())
+		}
+
+		server := version.Requirement{
+			Name:       "server",
+			Constraint: ">= 2.3",
+			GetVersion: func() (string, error) {
+				return "", nil
+			},
+		}
+		Expect(version.CheckRequirements("jcli", server)).NotTo(Succeed())
+	})
+})
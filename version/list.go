@@ -0,0 +1,75 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	gh "github.com/linuxsuren/cobra-extension/github"
+	"github.com/linuxsuren/cobra-extension/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewListCmd create a command for listing the releases
+func NewListCmd(org, repo, name string) (cmd *cobra.Command) {
+	opt := &ListOption{
+		Org:  org,
+		Repo: repo,
+	}
+
+	cmd = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   fmt.Sprintf("List the available releases of %s", name),
+		Long:    fmt.Sprintf("List the available releases of %s", name),
+		RunE:    opt.RunE,
+	}
+	opt.SetFlagWithTable(cmd, ReleaseItem{})
+	opt.RegisterFlagCompletion(cmd, []ReleaseItem{})
+	opt.addFlags(cmd.Flags())
+	return
+}
+
+func (o *ListOption) addFlags(flags *pflag.FlagSet) {
+	flags.IntVarP(&o.Count, "count", "", 10,
+		"The count of the releases")
+}
+
+// RunE is the main point of current command
+func (o *ListOption) RunE(cmd *cobra.Command, _ []string) (err error) {
+	ghClient := &gh.ReleaseClient{
+		Client: o.GitHubClient,
+		Org:    o.Org,
+		Repo:   o.Repo,
+	}
+	if ghClient.Client == nil {
+		ghClient.Init()
+	}
+
+	var releases []gh.Release
+	if releases, err = ghClient.GetReleaseList(o.Org, o.Repo, o.Count); err != nil {
+		err = fmt.Errorf("cannot get the releases of %s/%s, error: %v", o.Org, o.Repo, err)
+		return
+	}
+
+	currentVersion := strings.TrimPrefix(GetVersion(), "v")
+	items := make([]ReleaseItem, 0, len(releases))
+	for _, release := range releases {
+		items = append(items, ReleaseItem{
+			Tag:         release.TagName,
+			PublishedAt: release.PublishedAt,
+			Prerelease:  release.Prerelease,
+			Installed:   strings.TrimPrefix(release.TagName, "v") == currentVersion,
+		})
+	}
+
+	o.Writer = cmd.OutOrStdout()
+	o.CellRenderMap = map[string]pkg.RenderCell{
+		"PublishedAt": func(cell string) string {
+			// only keep the date part, e.g. 2021-03-01
+			return strings.Split(cell, " ")[0]
+		},
+	}
+	err = o.OutputV2(items)
+	return
+}
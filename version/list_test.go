@@ -0,0 +1,52 @@
+package version_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/linuxsuren/cobra-extension/github/githubtest"
+	"github.com/linuxsuren/cobra-extension/version"
+)
+
+var _ = Describe("version list command", func() {
+	It("flags", func() {
+		cmd := version.NewListCmd("o", "r", "fake")
+		Expect(cmd.Flags().Lookup("count")).NotTo(BeNil())
+		Expect(cmd.Flags().Lookup("output")).NotTo(BeNil())
+		Expect(cmd.Flags().Lookup("columns").DefValue).To(Equal("Tag,PublishedAt,Prerelease,Installed"))
+	})
+
+	It("list releases", func() {
+		server := githubtest.NewServer()
+		defer server.Close()
+		server.AddRelease("o", "r", githubtest.Release{
+			TagName:     "v0.0.1",
+			PublishedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		}).AddRelease("o", "r", githubtest.Release{
+			TagName:     "v0.0.2",
+			Prerelease:  true,
+			PublishedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+		})
+		version.SetVersion("v0.0.1")
+
+		opt := &version.ListOption{
+			Org:          "o",
+			Repo:         "r",
+			GitHubClient: server.Client(),
+		}
+		opt.Columns = "Tag,PublishedAt,Prerelease,Installed"
+
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{}
+		cmd.SetOut(buf)
+		Expect(opt.RunE(cmd, nil)).To(Succeed())
+		Expect(buf.String()).To(Equal(`Tag    PublishedAt Prerelease Installed
+v0.0.2 2021-03-02  true       false
+v0.0.1 2021-03-01  false      true
+`))
+	})
+})
@@ -1,9 +1,12 @@
 package version
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/go-github/v29/github"
+	"github.com/linuxsuren/cobra-extension/pkg"
 	"net/http"
+	"time"
 )
 
 // PrintOption is the version option
@@ -15,6 +18,25 @@ type PrintOption struct {
 	Repo string
 }
 
+// ListOption is the option of the version list command
+type ListOption struct {
+	pkg.OutputOption
+
+	Count int
+	Org   string
+	Repo  string
+
+	GitHubClient *github.Client
+}
+
+// ReleaseItem is a release in the list
+type ReleaseItem struct {
+	Tag         string    `table:"Tag"`
+	PublishedAt time.Time `table:"PublishedAt"`
+	Prerelease  bool      `table:"Prerelease"`
+	Installed   bool      `table:"Installed"`
+}
+
 // CustomDownloadFunc is the function interface for custom download URL
 type CustomDownloadFunc func(string) string
 
@@ -28,11 +50,73 @@ type SelfUpgradeOption struct {
 	CustomDownloadFunc CustomDownloadFunc
 	PathSeparate       string
 	Thread             int
+	DryRun             bool
+	Check              bool
+
+	// ChecksumFileName is the checksum file next to the download file, it's checksums.txt by default
+	ChecksumFileName string
+	// Companions are the files which are shipped together with the binary file in the same archive
+	Companions []CompanionFile
+	// CompletionArgs is the command to regenerate the completion scripts after upgrade, e.g. completion
+	CompletionArgs []string
+	// NoDownloadCount disables counting the download on GitHub when the file comes from a custom URL,
+	// e.g. when testing with a fake server offline
+	NoDownloadCount bool
 
 	GitHubClient *github.Client
 	RoundTripper http.RoundTripper
 }
 
+// CompanionFile is a file which needs to be installed together with the binary file
+type CompanionFile struct {
+	// Source is the member name in the archive
+	Source string
+	// Target is the install destination, a path starts with ~ is supported
+	Target string
+}
+
+// UpgradePlan describes what an upgrade is going to do
+type UpgradePlan struct {
+	CurrentVersion string
+	Version        string
+	URL            string
+	TargetPath     string
+	NeedPrivilege  bool
+	Checksum       string
+	UpToDate       bool
+}
+
+// UpdateAvailableError indicates there's a new version in the check mode
+type UpdateAvailableError struct {
+	CurrentVersion string
+	LatestVersion  string
+}
+
+// UpdateAvailableExitCode is the exit code when a new version is available in the check mode
+const UpdateAvailableExitCode = 100
+
+// Error returns the message of the error
+func (e *UpdateAvailableError) Error() string {
+	return fmt.Sprintf("a new version %s is available, current version is %s", e.LatestVersion, e.CurrentVersion)
+}
+
+// ExitCode returns the exit code of the error
+func (e *UpdateAvailableError) ExitCode() int {
+	return UpdateAvailableExitCode
+}
+
+// GetExitCode returns the exit code of an error which comes from the commands, the error could be wrapped
+func GetExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
+}
+
 var (
 	version string
 	commit  string
@@ -20,7 +20,9 @@ import (
 	"syscall"
 )
 
-// NewSelfUpgradeCmd create a command for self upgrade
+// NewSelfUpgradeCmd create a command for self upgrade.
+// The flag --check returns an UpdateAvailableError if there's a new version, the host should exit with
+// os.Exit(version.GetExitCode(err)) to get the exit code 100 instead of 1
 func NewSelfUpgradeCmd(org, repo, name string, customDownloadFunc CustomDownloadFunc) (cmd *cobra.Command) {
 	opt := &SelfUpgradeOption{
 		Org:                org,
@@ -49,6 +51,10 @@ func (o *SelfUpgradeOption) addFlags(flags *pflag.FlagSet) {
 		fmt.Sprintf("Try to take the privilege from system if there's no write permission on %s", o.Name))
 	flags.IntVarP(&o.Thread, "thread", "t", 0,
 		"Download the target binary file in multi-thread mode. It only works when its value is bigger than 1")
+	flags.BoolVarP(&o.DryRun, "dry-run", "", false,
+		"Only print what the upgrade would do without doing it")
+	flags.BoolVarP(&o.Check, "check", "", false,
+		fmt.Sprintf("Only check if there's a new version, exit with code %d if it's available", UpdateAvailableExitCode))
 }
 
 // RunE is the main point of current command
@@ -64,6 +70,11 @@ func (o *SelfUpgradeOption) RunE(cmd *cobra.Command, args []string) (err error)
 		err = fmt.Errorf("cannot find %s from system path, error: %v", o.Name, err)
 		return
 	}
+
+	if o.DryRun || o.Check {
+		err = o.runPlan(cmd, version, targetPath)
+		return
+	}
 	cmd.Printf("prepare to upgrade %s\n", targetPath)
 
 	var f *os.File
@@ -91,15 +102,55 @@ func (o *SelfUpgradeOption) RunE(cmd *cobra.Command, args []string) (err error)
 	return
 }
 
-// Download downloads the binary file from GitHub release
-// Org, Repo, Name is necessary
-func (o *SelfUpgradeOption) Download(log common.Printer, version, currentVersion, targetPath string) (err error) {
+func (o *SelfUpgradeOption) runPlan(cmd *cobra.Command, version, targetPath string) (err error) {
+	var plan *UpgradePlan
+	if plan, err = o.Plan(version, GetVersion(), targetPath); err != nil {
+		return
+	}
+
+	if o.DryRun {
+		cmd.Printf("current version: %s\n", plan.CurrentVersion)
+		cmd.Printf("target version: %s\n", plan.Version)
+		cmd.Printf("target path: %s\n", plan.TargetPath)
+		cmd.Printf("need privilege: %v\n", plan.NeedPrivilege)
+		if !plan.UpToDate {
+			cmd.Printf("download URL: %s\n", plan.URL)
+			if plan.Checksum == "" {
+				cmd.Println("checksum: none")
+			} else {
+				cmd.Printf("checksum: sha256:%s\n", plan.Checksum)
+			}
+			for _, companion := range o.Companions {
+				cmd.Printf("companion file: %s -> %s\n", companion.Source, companion.Target)
+			}
+		}
+	}
+
+	if plan.UpToDate {
+		cmd.Printf("no need to upgrade %s\n", o.Name)
+	} else if o.Check {
+		err = &UpdateAvailableError{
+			CurrentVersion: plan.CurrentVersion,
+			LatestVersion:  plan.Version,
+		}
+		cmd.Println(err.Error())
+		// the message was printed already, only the exit code matters
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return
+}
+
+// Plan figures out what the upgrade is going to do without doing it
+func (o *SelfUpgradeOption) Plan(version, currentVersion, targetPath string) (plan *UpgradePlan, err error) {
 	// try to understand the version from user input
 	switch version {
 	case "dev":
 		version = "master"
 	case "":
-		o.GitHubClient = github.NewClient(nil)
+		if o.GitHubClient == nil {
+			o.GitHubClient = github.NewClient(nil)
+		}
 		ghClient := &gh.ReleaseClient{
 			Client: o.GitHubClient,
 			Org:    o.Org,
@@ -113,35 +164,58 @@ func (o *SelfUpgradeOption) Download(log common.Printer, version, currentVersion
 		}
 	}
 
-	// version review
-	if strings.TrimPrefix(currentVersion, "v") == strings.TrimPrefix(version, "v") {
-		log.Printf("no need to upgrade %s\n", o.Name)
+	plan = &UpgradePlan{
+		CurrentVersion: currentVersion,
+		Version:        version,
+		TargetPath:     targetPath,
+		NeedPrivilege:  !isWritable(targetPath),
+		// version review
+		UpToDate: strings.TrimPrefix(currentVersion, "v") == strings.TrimPrefix(version, "v"),
+	}
+	if plan.UpToDate {
 		return
 	}
-	log.Println(fmt.Sprintf("prepare to upgrade to %s", version))
-
-	// download the tar file of target file
-	tmpDir := os.TempDir()
-	output := fmt.Sprintf("%s/%s.tar.gz", tmpDir, o.Name)
 
 	if o.PathSeparate == "" {
 		o.PathSeparate = "-"
 	}
 
-	var fileURL string
 	if o.CustomDownloadFunc == nil {
-		fileURL = fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s%s%s%s%s.tar.gz",
+		plan.URL = fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s%s%s%s%s.tar.gz",
 			o.Org, o.Repo, version, o.Name, o.PathSeparate, runtime.GOOS, o.PathSeparate, runtime.GOARCH)
 	} else {
-		fileURL = o.CustomDownloadFunc(version)
-
-		// only do the count when the source is not belong to github.com
-		if !strings.HasPrefix(fileURL, "https://github.com") {
-			// make sure we count the download action
-			go func() {
-				o.downloadCount(version, runtime.GOOS)
-			}()
-		}
+		plan.URL = o.CustomDownloadFunc(version)
+	}
+	plan.Checksum, err = o.getChecksum(plan.URL)
+	return
+}
+
+// Download downloads the binary file from GitHub release
+// Org, Repo, Name is necessary
+func (o *SelfUpgradeOption) Download(log common.Printer, version, currentVersion, targetPath string) (err error) {
+	var plan *UpgradePlan
+	if plan, err = o.Plan(version, currentVersion, targetPath); err != nil {
+		return
+	}
+
+	if plan.UpToDate {
+		log.Printf("no need to upgrade %s\n", o.Name)
+		return
+	}
+	version = plan.Version
+	log.Println(fmt.Sprintf("prepare to upgrade to %s", version))
+
+	// download the tar file of target file
+	tmpDir := os.TempDir()
+	output := fmt.Sprintf("%s/%s.tar.gz", tmpDir, o.Name)
+
+	fileURL := plan.URL
+	// only do the count when the source is not belong to github.com
+	if o.CustomDownloadFunc != nil && !o.NoDownloadCount && !strings.HasPrefix(fileURL, "https://github.com") {
+		// make sure we count the download action
+		go func() {
+			o.downloadCount(version, runtime.GOOS)
+		}()
 	}
 	log.Println("start to download from", fileURL)
 
@@ -168,28 +242,44 @@ func (o *SelfUpgradeOption) Download(log common.Printer, version, currentVersion
 		}
 	}
 
-	if err = o.extractFiles(output); err == nil {
-		err = o.overWriteBinary(fmt.Sprintf("%s/%s", filepath.Dir(output), o.Name), targetPath)
-	} else {
+	if plan.Checksum != "" {
+		if err = verifyChecksum(output, plan.Checksum); err != nil {
+			return
+		}
+	}
+
+	if err = o.extractFiles(output); err != nil {
 		err = fmt.Errorf("cannot extract %s from tar file, error: %v", o.Name, err)
+		return
 	}
-	return
-}
 
-func (o *SelfUpgradeOption) overWriteBinary(sourceFile, targetPath string) (err error) {
-	switch runtime.GOOS {
-	case "linux":
-		var cp string
-		if cp, err = exec.LookPath("cp"); err == nil {
-			err = syscall.Exec(cp, []string{"cp", sourceFile, targetPath}, os.Environ())
-		}
-	default:
-		sourceF, _ := os.Open(sourceFile)
-		targetF, _ := os.OpenFile(targetPath, os.O_CREATE|os.O_RDWR, 0664)
-		if _, err = io.Copy(targetF, sourceF); err != nil {
-			err = fmt.Errorf("cannot copy %s from %s to %v, error: %v", o.Name, sourceFile, targetPath, err)
+	// stage the binary file next to the target, then it could be renamed and rolled back together with the companions
+	tmpBinary := fmt.Sprintf("%s/%s", filepath.Dir(output), o.Name)
+	stagedBinary := fmt.Sprintf("%s.%s-upgrade", targetPath, o.Name)
+	if err = copyFile(tmpBinary, stagedBinary); err != nil {
+		err = fmt.Errorf("cannot stage the binary file of %s, error: %v", o.Name, err)
+		return
+	}
+	defer func() {
+		_ = os.Remove(stagedBinary)
+	}()
+
+	rollback := func() {}
+	if len(o.Companions) > 0 {
+		var cleanup func()
+		if rollback, cleanup, err = o.installCompanions(filepath.Dir(output)); err != nil {
+			err = fmt.Errorf("cannot install the companion files of %s, error: %v", o.Name, err)
+			return
 		}
+		defer cleanup()
+	}
+
+	if err = os.Rename(stagedBinary, targetPath); err != nil {
+		rollback()
+		err = fmt.Errorf("cannot overwrite %s, error: %v", targetPath, err)
+		return
 	}
+	o.regenerateCompletion(log, targetPath)
 	return
 }
 
@@ -197,7 +287,7 @@ func (o *SelfUpgradeOption) downloadCount(version string, arch string) {
 	countURL := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s-%s-%s.tar.gz",
 		o.Org, o.Repo, version, o.Name, runtime.GOOS, runtime.GOARCH)
 
-	if tempDir, err := ioutil.TempDir(".", "download-count"); err == nil {
+	if tempDir, err := ioutil.TempDir("", "download-count"); err == nil {
 		tempFile := tempDir + fmt.Sprintf("/%s.tar.gz", o.Name)
 		defer func() {
 			_ = os.RemoveAll(tempDir)
@@ -240,18 +330,29 @@ func (o *SelfUpgradeOption) extractFiles(tarFile string) (err error) {
 
 		switch header.Typeflag {
 		case tar.TypeReg:
-			if name != o.Name {
+			var targetPath string
+			if name == o.Name {
+				targetPath = fmt.Sprintf("%s/%s", filepath.Dir(tarFile), name)
+			} else if index := o.findCompanion(name); index >= 0 {
+				targetPath = o.stagedCompanionPath(filepath.Dir(tarFile), index)
+				if err = os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+					return
+				}
+			} else {
 				continue
 			}
+
 			var targetFile *os.File
-			if targetFile, err = os.OpenFile(fmt.Sprintf("%s/%s", filepath.Dir(tarFile), name),
-				os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode)); err != nil {
-				break
-			}
-			if _, err = io.Copy(targetFile, tarReader); err != nil {
-				break
+			if targetFile, err = os.OpenFile(targetPath,
+				os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode)); err != nil {
+				return
 			}
+			_, err = io.Copy(targetFile, tarReader)
 			_ = targetFile.Close()
+			if err != nil {
+				err = fmt.Errorf("cannot extract %s, error: %v", name, err)
+				return
+			}
 		}
 	}
 	return
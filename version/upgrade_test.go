@@ -0,0 +1,286 @@
+package version_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/linuxsuren/cobra-extension/github/githubtest"
+	"github.com/linuxsuren/cobra-extension/version"
+)
+
+var _ = Describe("upgrade plan", func() {
+	var (
+		server *httptest.Server
+		opt    *version.SelfUpgradeOption
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v0.0.2/checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "ABC  other.tar.gz\nDEF  fake.tar.gz\n")
+		})
+		mux.HandleFunc("/v0.0.4/checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		server = httptest.NewServer(mux)
+
+		opt = &version.SelfUpgradeOption{
+			Name: "fake",
+			CustomDownloadFunc: func(ver string) string {
+				return fmt.Sprintf("%s/%s/fake.tar.gz", server.URL, ver)
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("same version", func() {
+		plan, err := opt.Plan("v0.0.1", "0.0.1", "/fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.UpToDate).To(BeTrue())
+		Expect(plan.URL).To(BeEmpty())
+	})
+
+	It("with checksum", func() {
+		plan, err := opt.Plan("v0.0.2", "v0.0.1", "/fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.UpToDate).To(BeFalse())
+		Expect(plan.Version).To(Equal("v0.0.2"))
+		Expect(plan.URL).To(Equal(server.URL + "/v0.0.2/fake.tar.gz"))
+		Expect(plan.Checksum).To(Equal("def"))
+	})
+
+	It("without checksum", func() {
+		plan, err := opt.Plan("v0.0.3", "v0.0.1", "/fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Checksum).To(BeEmpty())
+	})
+
+	It("cannot get the checksum", func() {
+		_, err := opt.Plan("v0.0.4", "v0.0.1", "/fake")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("command", func() {
+		var (
+			path   string
+			dir    string
+			buffer *bytes.Buffer
+			cmd    *cobra.Command
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "upgrade")
+			Expect(err).NotTo(HaveOccurred())
+			// the command finds the binary file from the system path
+			Expect(ioutil.WriteFile(filepath.Join(dir, "fake"), []byte("fake"), 0755)).To(Succeed())
+			path = os.Getenv("PATH")
+			Expect(os.Setenv("PATH", dir+string(os.PathListSeparator)+path)).To(Succeed())
+			version.SetVersion("v0.0.1")
+
+			buffer = new(bytes.Buffer)
+			cmd = version.NewSelfUpgradeCmd("o", "r", "fake", opt.CustomDownloadFunc)
+			cmd.SetOut(buffer)
+			cmd.SetErr(buffer)
+		})
+
+		AfterEach(func() {
+			version.SetVersion("")
+			Expect(os.Setenv("PATH", path)).To(Succeed())
+			_ = os.RemoveAll(dir)
+		})
+
+		It("dry run", func() {
+			cmd.SetArgs([]string{"v0.0.2", "--dry-run"})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(buffer.String()).To(Equal("current version: v0.0.1\n" +
+				"target version: v0.0.2\n" +
+				"target path: " + filepath.Join(dir, "fake") + "\n" +
+				"need privilege: false\n" +
+				"download URL: " + server.URL + "/v0.0.2/fake.tar.gz\n" +
+				"checksum: sha256:def\n"))
+
+			data, _ := ioutil.ReadFile(filepath.Join(dir, "fake"))
+			Expect(string(data)).To(Equal("fake"))
+		})
+
+		It("check", func() {
+			cmd.SetArgs([]string{"v0.0.2", "--check"})
+			err := cmd.Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(version.GetExitCode(err)).To(Equal(version.UpdateAvailableExitCode))
+			Expect(buffer.String()).To(Equal("a new version v0.0.2 is available, current version is v0.0.1\n"))
+		})
+
+		It("check the same version", func() {
+			cmd.SetArgs([]string{"v0.0.1", "--check"})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(buffer.String()).To(Equal("no need to upgrade fake\n"))
+		})
+	})
+
+	It("exit code", func() {
+		Expect(version.GetExitCode(nil)).To(Equal(0))
+		Expect(version.GetExitCode(errors.New("fake"))).To(Equal(1))
+		Expect(version.GetExitCode(&version.UpdateAvailableError{})).To(Equal(version.UpdateAvailableExitCode))
+		Expect(version.GetExitCode(fmt.Errorf("wrapped: %w", &version.UpdateAvailableError{}))).
+			To(Equal(version.UpdateAvailableExitCode))
+	})
+
+	It("latest version from the fake server", func() {
+		fake := githubtest.NewServer()
+		defer fake.Close()
+		fake.AddRelease("o", "r", githubtest.Release{
+			TagName: "v0.0.5",
+			Assets: []githubtest.Asset{{
+				Name:    "checksums.txt",
+				Content: []byte("abc  fake.tar.gz"),
+			}},
+		})
+
+		opt.Org, opt.Repo = "o", "r"
+		opt.GitHubClient = fake.Client()
+		opt.CustomDownloadFunc = func(ver string) string {
+			return fake.DownloadURL("o", "r", ver, "fake.tar.gz")
+		}
+
+		plan, err := opt.Plan("", "v0.0.1", "/fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Version).To(Equal("v0.0.5"))
+		Expect(plan.Checksum).To(Equal("abc"))
+	})
+})
+
+// recordTransport records the hosts of the requests
+type recordTransport struct {
+	lock  sync.Mutex
+	hosts []string
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	t.hosts = append(t.hosts, req.URL.Host)
+	t.lock.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+var _ = Describe("download from the fake server", func() {
+	var (
+		dir       string
+		binary    string
+		server    *githubtest.Server
+		transport *recordTransport
+		opt       *version.SelfUpgradeOption
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "download")
+		Expect(err).NotTo(HaveOccurred())
+
+		// the new binary records the arguments, it tells if the completion is regenerated
+		binary = fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\n", filepath.Join(dir, "completion"))
+
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for name, content := range map[string]string{"fake": binary, "fake.bash": "new completion"} {
+			Expect(tarWriter.WriteHeader(&tar.Header{
+				Name:     name,
+				Typeflag: tar.TypeReg,
+				Mode:     0755,
+				Size:     int64(len(content)),
+			})).To(Succeed())
+			_, err = tarWriter.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+
+		server = githubtest.NewServer()
+		server.AddRelease("o", "r", githubtest.Release{
+			TagName: "v0.0.2",
+			Assets: []githubtest.Asset{{
+				Name:    "fake.tar.gz",
+				Content: buffer.Bytes(),
+			}},
+		})
+
+		transport = &recordTransport{}
+		opt = &version.SelfUpgradeOption{
+			Org:          "o",
+			Repo:         "r",
+			Name:         "fake",
+			GitHubClient: server.Client(),
+			RoundTripper: transport,
+			CustomDownloadFunc: func(ver string) string {
+				return server.DownloadURL("o", "r", ver, "fake.tar.gz")
+			},
+			NoDownloadCount: true,
+			Companions: []version.CompanionFile{{
+				Source: "fake.bash",
+				Target: filepath.Join(dir, "fake.bash"),
+			}},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	It("without any outside request", func() {
+		target := filepath.Join(dir, "fake")
+		Expect(ioutil.WriteFile(target, []byte("old binary"), 0755)).To(Succeed())
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		Expect(opt.Download(cmd, "v0.0.2", "v0.0.1", target)).To(Succeed())
+
+		data, _ := ioutil.ReadFile(target)
+		Expect(string(data)).To(Equal(binary))
+		data, _ = ioutil.ReadFile(opt.Companions[0].Target)
+		Expect(string(data)).To(Equal("new completion"))
+
+		serverURL, _ := url.Parse(server.URL)
+		Expect(transport.hosts).NotTo(BeEmpty())
+		for _, host := range transport.hosts {
+			Expect(host).To(Equal(serverURL.Host))
+		}
+		counts, _ := filepath.Glob("download-count*")
+		Expect(counts).To(BeEmpty())
+	})
+	It("regenerate the completion without companions", func() {
+		target := filepath.Join(dir, "fake")
+		Expect(ioutil.WriteFile(target, []byte("old binary"), 0755)).To(Succeed())
+		opt.Companions = nil
+		opt.CompletionArgs = []string{"completion", "--install"}
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		Expect(opt.Download(cmd, "v0.0.2", "v0.0.1", target)).To(Succeed())
+
+		data, _ := ioutil.ReadFile(target)
+		Expect(string(data)).To(Equal(binary))
+		data, err := ioutil.ReadFile(filepath.Join(dir, "completion"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("completion --install\n"))
+	})
+})
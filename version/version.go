@@ -27,7 +27,8 @@ func NewVersionCmd(org, repo, name string, customDownloadFunc CustomDownloadFunc
 	flags := cmd.Flags()
 	opt.addFlags(flags)
 
-	cmd.AddCommand(NewSelfUpgradeCmd(org, repo, name, customDownloadFunc))
+	cmd.AddCommand(NewSelfUpgradeCmd(org, repo, name, customDownloadFunc),
+		NewListCmd(org, repo, name))
 	return
 }
 